@@ -0,0 +1,311 @@
+package account
+
+/*
+	소셜 계정 연결 (한 사용자에 여러 OAuth 계정)
+	- provider + subject 로 사용자를 찾고, 없으면 인증된 이메일이 같은 사용자에 연결하거나 새로 만듭니다.
+	- UserIdentities 이전에 가입한 사용자(Users.Provider)는 첫 로그인 시 identity 를 채워 넣습니다.
+*/
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/JokerTrickster/common/db/mysql"
+	_error "github.com/JokerTrickster/common/error"
+	"github.com/JokerTrickster/common/oauth"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// findOrCreateSavePoint is rolled back to when a concurrent first login created the identity first
+const findOrCreateSavePoint = "find_or_create"
+
+// LegacyLookup finds the user that signed up with the provider before UserIdentities existed.
+// It returns nil when there is no such user.
+type LegacyLookup func(ctx context.Context, tx *gorm.DB, data oauth.OAuthData) (*mysql.Users, error)
+
+// Service manages users and their linked OAuth identities
+type Service struct {
+	db           *gorm.DB
+	legacyLookup LegacyLookup
+}
+
+// ServiceOption configures a Service
+type ServiceOption func(*Service)
+
+// WithLegacyLookup replaces the default legacy lookup (Users.Provider + email).
+// Use it when legacy rows can be matched another way (ex. users without an email).
+func WithLegacyLookup(lookup LegacyLookup) ServiceOption {
+	return func(s *Service) {
+		s.legacyLookup = lookup
+	}
+}
+
+// NewService creates an account service
+func NewService(db *gorm.DB, opts ...ServiceOption) *Service {
+	s := &Service{db: db, legacyLookup: LegacyLookupByEmail}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LegacyLookupByEmail matches a legacy user by Users.Provider and email.
+// Users without an email cannot be matched and need WithLegacyLookup.
+func LegacyLookupByEmail(ctx context.Context, tx *gorm.DB, data oauth.OAuthData) (*mysql.Users, error) {
+	email := normalizeEmail(data.Email)
+	if email == "" {
+		return nil, nil
+	}
+	var users []mysql.Users
+	if err := tx.Where("provider = ? AND email = ?", data.Provider, email).Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for i := range users {
+		// 이미 이 provider 의 identity 가 있는 사용자는 legacy 가 아닙니다.
+		var count int64
+		if err := tx.Model(&mysql.UserIdentities{}).Where("user_id = ? AND provider = ?", users[i].ID, data.Provider).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return &users[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// FindOrCreate returns the user for the OAuth identity.
+// If the identity is unknown it is backfilled for the legacy user of the same provider,
+// or linked to the user with the same email when both the provider and that user verified it;
+// otherwise a new user is created.
+// created reports whether a user was created.
+func (s *Service) FindOrCreate(ctx context.Context, data oauth.OAuthData) (user *mysql.Users, created bool, err error) {
+	if data.Provider == "" || data.ID == "" {
+		return nil, false, _error.CreateError(ctx, string(_error.ErrBadParameter), _error.Trace(), "provider and subject are required", string(_error.ErrFromClient))
+	}
+	email := normalizeEmail(data.Email)
+
+	err = mysql.Transaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		found, err := findByIdentity(tx, data, email)
+		if err != nil || found != nil {
+			user = found
+			return err
+		}
+
+		if err := tx.SavePoint(findOrCreateSavePoint).Error; err != nil {
+			return err
+		}
+		user, created, err = s.linkOrCreate(ctx, tx, data, email)
+		if err == nil || !mysql.IsDuplicateKeyError(err) {
+			return err
+		}
+		// 같은 identity 로 동시에 첫 로그인한 요청이 먼저 커밋했으면 그 사용자를 반환합니다.
+		// 잠금 읽기로 트랜잭션 스냅샷이 아닌 최신 커밋 행을 읽습니다.
+		if err := tx.RollbackTo(findOrCreateSavePoint).Error; err != nil {
+			return err
+		}
+		created = false
+		user, err = findByIdentity(tx.Clauses(clause.Locking{Strength: "SHARE"}), data, email)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			// provider + subject 가 아닌 user + provider 인덱스 충돌입니다.
+			return _error.CreateError(ctx, string(_error.ErrIdentityAlreadyLinked), _error.Trace(), "user already has an identity for "+data.Provider, string(_error.ErrFromClient))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, dbError(ctx, err, "failed to find or create user")
+	}
+	return user, created, nil
+}
+
+// MarkEmailVerified records that the user verified their email (ex. with an auth code after sign up).
+// Only users with a verified email can be merged with an OAuth login of the same email.
+func (s *Service) MarkEmailVerified(ctx context.Context, userID uint) error {
+	err := s.db.WithContext(ctx).Model(&mysql.Users{}).Where("id = ?", userID).Update("email_verified", true).Error
+	return dbError(ctx, err, "failed to mark email verified")
+}
+
+// Link links an OAuth identity to a logged-in user.
+// It fails when the identity belongs to another user or the user already has that provider.
+func (s *Service) Link(ctx context.Context, userID uint, data oauth.OAuthData) error {
+	if data.Provider == "" || data.ID == "" {
+		return _error.CreateError(ctx, string(_error.ErrBadParameter), _error.Trace(), "provider and subject are required", string(_error.ErrFromClient))
+	}
+	err := mysql.Transaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		var identity mysql.UserIdentities
+		err := tx.Where("provider = ? AND subject = ?", data.Provider, data.ID).First(&identity).Error
+		if err == nil {
+			if identity.UserID == userID {
+				return nil
+			}
+			return _error.CreateError(ctx, string(_error.ErrIdentityAlreadyLinked), _error.Trace(), "identity is linked to another user", string(_error.ErrFromClient))
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := tx.First(&mysql.Users{}, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return _error.CreateError(ctx, string(_error.ErrUserNotFound), _error.Trace(), "user not found", string(_error.ErrFromClient))
+			}
+			return err
+		}
+		if err := s.checkProviderFree(ctx, tx, userID, data.Provider); err != nil {
+			return err
+		}
+		return tx.Create(newIdentity(userID, data.Provider, data.ID, normalizeEmail(data.Email))).Error
+	})
+	return dbError(ctx, err, "failed to link identity")
+}
+
+// Unlink removes the user's identity for the provider.
+// The last identity of a user without a password cannot be removed.
+func (s *Service) Unlink(ctx context.Context, userID uint, provider oauth.AuthProvider) error {
+	err := mysql.Transaction(s.db.WithContext(ctx), func(tx *gorm.DB) error {
+		var user mysql.Users
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return _error.CreateError(ctx, string(_error.ErrUserNotFound), _error.Trace(), "user not found", string(_error.ErrFromClient))
+			}
+			return err
+		}
+
+		var identities []mysql.UserIdentities
+		if err := tx.Where("user_id = ?", userID).Find(&identities).Error; err != nil {
+			return err
+		}
+		var target *mysql.UserIdentities
+		for i := range identities {
+			if identities[i].Provider == provider.String() {
+				target = &identities[i]
+			}
+		}
+		if target == nil {
+			return _error.CreateError(ctx, string(_error.ErrNotFound), _error.Trace(), "identity not found", string(_error.ErrFromClient))
+		}
+		if len(identities) == 1 && user.Password == "" {
+			return _error.CreateError(ctx, string(_error.ErrLastIdentity), _error.Trace(), "cannot unlink the last login method", string(_error.ErrFromClient))
+		}
+		// 같은 계정을 다시 연결할 수 있도록 unique 인덱스에서 제외되게 완전히 삭제합니다.
+		return tx.Unscoped().Delete(target).Error
+	})
+	return dbError(ctx, err, "failed to unlink identity")
+}
+
+// Identities returns the identities linked to the user
+func (s *Service) Identities(ctx context.Context, userID uint) ([]mysql.UserIdentities, error) {
+	var identities []mysql.UserIdentities
+	if err := s.db.WithContext(ctx).Where("user_id = ?", userID).Order("linked_at").Find(&identities).Error; err != nil {
+		return nil, dbError(ctx, err, "failed to get identities")
+	}
+	return identities, nil
+}
+
+// checkProviderFree rejects a second identity of the same provider for a user
+func (s *Service) checkProviderFree(ctx context.Context, tx *gorm.DB, userID uint, provider string) error {
+	var count int64
+	if err := tx.Model(&mysql.UserIdentities{}).Where("user_id = ? AND provider = ?", userID, provider).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return _error.CreateError(ctx, string(_error.ErrIdentityAlreadyLinked), _error.Trace(), "user already has an identity for "+provider, string(_error.ErrFromClient))
+	}
+	return nil
+}
+
+// findByIdentity returns the user linked to the OAuth identity, or nil when the identity is unknown.
+// It keeps the identity email in sync with the provider.
+func findByIdentity(tx *gorm.DB, data oauth.OAuthData, email string) (*mysql.Users, error) {
+	var identity mysql.UserIdentities
+	err := tx.Where("provider = ? AND subject = ?", data.Provider, data.ID).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	user := &mysql.Users{}
+	if err := tx.First(user, identity.UserID).Error; err != nil {
+		return nil, err
+	}
+	if email != "" && identity.Email != email {
+		if err := tx.Model(&identity).Update("email", email).Error; err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// linkOrCreate links an unknown identity to the legacy user or the user with the same verified email,
+// or creates a new user for it
+func (s *Service) linkOrCreate(ctx context.Context, tx *gorm.DB, data oauth.OAuthData, email string) (*mysql.Users, bool, error) {
+	if s.legacyLookup != nil {
+		legacy, err := s.legacyLookup(ctx, tx, data)
+		if err != nil {
+			return nil, false, err
+		}
+		if legacy != nil {
+			return legacy, false, tx.Create(newIdentity(legacy.ID, data.Provider, data.ID, email)).Error
+		}
+	}
+
+	var existing mysql.Users
+	if email != "" {
+		err := tx.Where("email = ?", email).First(&existing).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, err
+		}
+	}
+	if existing.ID != 0 {
+		// 어느 한쪽이라도 이메일을 인증하지 않았으면 계정 탈취가 가능하므로 병합하지 않습니다.
+		if !data.EmailVerified || !existing.EmailVerified {
+			return nil, false, _error.CreateError(ctx, string(_error.ErrUserAlreadyExisted), _error.Trace(), "email is used by another account; log in and link this provider", string(_error.ErrFromClient))
+		}
+		if err := s.checkProviderFree(ctx, tx, existing.ID, data.Provider); err != nil {
+			return nil, false, err
+		}
+		return &existing, false, tx.Create(newIdentity(existing.ID, data.Provider, data.ID, email)).Error
+	}
+
+	user := &mysql.Users{
+		Email:         email,
+		EmailVerified: email != "" && data.EmailVerified,
+		Name:          data.Name,
+		Image:         data.Image,
+		Provider:      data.Provider,
+	}
+	if err := tx.Create(user).Error; err != nil {
+		return nil, false, err
+	}
+	return user, true, tx.Create(newIdentity(user.ID, data.Provider, data.ID, email)).Error
+}
+
+func newIdentity(userID uint, provider, subject, email string) *mysql.UserIdentities {
+	return &mysql.UserIdentities{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+		LinkedAt: time.Now().Unix(),
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// dbError keeps AppErrors and wraps other errors as INTERNAL_DB
+func dbError(ctx context.Context, err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := _error.AsAppError(err); ok {
+		return err
+	}
+	return _error.Wrap(ctx, err, string(_error.ErrInternalDB), _error.Trace(), msg, string(_error.ErrFromMysqlDB))
+}
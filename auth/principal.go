@@ -0,0 +1,91 @@
+package auth
+
+/*
+	인증된 사용자 정보 (Principal) 및 context 접근자
+*/
+
+import (
+	"context"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Principal is the authenticated user of a request
+type Principal struct {
+	UserID   uint     `json:"userID"`
+	Email    string   `json:"email"`
+	Roles    []string `json:"roles,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Provider string   `json:"provider,omitempty"`
+	TokenID  string   `json:"tokenID,omitempty"`
+}
+
+// principalKey is the context.Context key for the principal
+type principalKey struct{}
+
+// echoPrincipalKey is the echo.Context key for the principal
+const echoPrincipalKey = "_auth_principal"
+
+// HasRole reports whether the principal has the role
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasScope reports whether the principal has the scope
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+// NewContext returns a copy of ctx carrying the principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// MustFromContext returns the principal stored in ctx and panics if there is none.
+// Use it only behind jwt.TokenChecker.
+func MustFromContext(ctx context.Context) *Principal {
+	p, ok := FromContext(ctx)
+	if !ok {
+		panic("auth: no principal in context")
+	}
+	return p
+}
+
+// SetEcho stores the principal in the echo.Context and its request context
+func SetEcho(c echo.Context, p *Principal) {
+	c.Set(echoPrincipalKey, p)
+	req := c.Request()
+	c.SetRequest(req.WithContext(NewContext(req.Context(), p)))
+}
+
+// FromEcho returns the principal stored in the echo.Context
+func FromEcho(c echo.Context) (*Principal, bool) {
+	if p, ok := c.Get(echoPrincipalKey).(*Principal); ok && p != nil {
+		return p, true
+	}
+	return FromContext(c.Request().Context())
+}
+
+// MustFromEcho returns the principal stored in the echo.Context and panics if there is none
+func MustFromEcho(c echo.Context) *Principal {
+	p, ok := FromEcho(c)
+	if !ok {
+		panic("auth: no principal in echo context")
+	}
+	return p
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
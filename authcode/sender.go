@@ -0,0 +1,22 @@
+package authcode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/JokerTrickster/common/aws"
+)
+
+// NewSESSender queues the code on the SES email queue with the template mapped to its purpose
+// (aws.InitAwsSes must be called first). Unknown purposes are rejected.
+//
+//	sender := authcode.NewSESSender(map[string]string{"signup": "foodAuth", "password": "foodPassword"})
+func NewSESSender(templates map[string]string) Sender {
+	return SenderFunc(func(ctx context.Context, email, purpose, code string) error {
+		templateName, ok := templates[purpose]
+		if !ok {
+			return fmt.Errorf("no SES template for auth code purpose: %s", purpose)
+		}
+		return aws.EmailSendAuthCodeTemplate(templateName, email, code)
+	})
+}
@@ -0,0 +1,288 @@
+package authcode
+
+/*
+	이메일 인증 코드 발급 및 검증
+	- 암호학적으로 안전한 숫자 코드를 생성하고 해시만 저장합니다.
+	- 이메일/용도별로 재발송 간격, 발송 횟수, 검증 시도 횟수를 제한합니다.
+*/
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+var (
+	// ErrRecordNotFound is returned by a Store when no record exists
+	ErrRecordNotFound = errors.New("auth code record not found")
+	// ErrResendTooSoon is returned by ReserveSend within the resend interval
+	ErrResendTooSoon = errors.New("auth code was sent recently")
+	// ErrSendLimitReached is returned by ReserveSend when the send window is used up
+	ErrSendLimitReached = errors.New("auth code send limit exceeded")
+)
+
+// Record is the stored state of an email/purpose pair
+type Record struct {
+	Email           string
+	Purpose         string
+	CodeHash        string // empty after the code is used
+	ExpiresAt       int64
+	Attempts        int
+	SendCount       int
+	LastSentAt      int64
+	WindowStartedAt int64
+}
+
+// Store persists auth code records (RedisStore, GormStore)
+type Store interface {
+	// Get returns ErrRecordNotFound when there is no record
+	Get(ctx context.Context, email, purpose string) (*Record, error)
+	// ReserveSend atomically checks the send limits and records a send at now.
+	// When a limit is hit it records nothing and returns ErrResendTooSoon or
+	// ErrSendLimitReached with the time to wait.
+	ReserveSend(ctx context.Context, email, purpose string, now time.Time, limits SendLimits) (time.Duration, error)
+	// ReleaseSend undoes the send reserved at sentAt (epoch second) when delivery failed
+	ReleaseSend(ctx context.Context, email, purpose string, sentAt int64) error
+	// SaveCode stores the hash of a delivered code, resets the attempts and keeps the record for at least ttl
+	SaveCode(ctx context.Context, email, purpose, codeHash string, expiresAt int64, ttl time.Duration) error
+	// IncrementAttempts atomically increments and returns the attempt count.
+	// It returns ErrRecordNotFound when the record no longer exists.
+	IncrementAttempts(ctx context.Context, email, purpose string) (int, error)
+	// Consume atomically clears the code and attempts if the stored hash is still codeHash.
+	// It reports false when the code was already used or replaced.
+	Consume(ctx context.Context, email, purpose, codeHash string) (bool, error)
+}
+
+// SendLimits are the send limits checked by Store.ReserveSend
+type SendLimits struct {
+	ResendInterval time.Duration
+	MaxSends       int
+	Window         time.Duration
+}
+
+// reserveSend applies the limits to record and records a send at now.
+// Stores without server-side scripting call it while holding a lock on the record.
+func reserveSend(record *Record, now time.Time, limits SendLimits) (time.Duration, error) {
+	if record.LastSentAt > 0 {
+		if wait := time.Unix(record.LastSentAt, 0).Add(limits.ResendInterval).Sub(now); wait > 0 {
+			return wait, ErrResendTooSoon
+		}
+	}
+	if record.WindowStartedAt == 0 || now.Sub(time.Unix(record.WindowStartedAt, 0)) >= limits.Window {
+		record.SendCount = 0
+		record.WindowStartedAt = now.Unix()
+	}
+	if record.SendCount >= limits.MaxSends {
+		return time.Unix(record.WindowStartedAt, 0).Add(limits.Window).Sub(now), ErrSendLimitReached
+	}
+	record.SendCount++
+	record.LastSentAt = now.Unix()
+	return 0, nil
+}
+
+// releaseSend undoes the send reserveSend recorded at sentAt and reports whether it did
+func releaseSend(record *Record, sentAt int64) bool {
+	if record.LastSentAt != sentAt || record.SendCount == 0 {
+		return false
+	}
+	record.SendCount--
+	record.LastSentAt = 0
+	return true
+}
+
+// Sender delivers a code to the user
+type Sender interface {
+	SendCode(ctx context.Context, email, purpose, code string) error
+}
+
+// SenderFunc adapts a function to Sender
+type SenderFunc func(ctx context.Context, email, purpose, code string) error
+
+func (f SenderFunc) SendCode(ctx context.Context, email, purpose, code string) error {
+	return f(ctx, email, purpose, code)
+}
+
+// Config defines the code format and limits
+type Config struct {
+	CodeLength     int           // digits, default 6
+	TTL            time.Duration // code lifetime, default 5m
+	MaxAttempts    int           // wrong codes before the code is invalidated, default 5
+	ResendInterval time.Duration // minimum time between sends, default 1m
+	MaxSends       int           // sends per SendWindow, default 5
+	SendWindow     time.Duration // default 1h
+	Secret         []byte        // HMAC key for code hashes, required (ex. 32 random bytes from SSM)
+}
+
+// DefaultConfig is used for zero fields of the Config passed to NewService
+var DefaultConfig = Config{
+	CodeLength:     6,
+	TTL:            5 * time.Minute,
+	MaxAttempts:    5,
+	ResendInterval: time.Minute,
+	MaxSends:       5,
+	SendWindow:     time.Hour,
+}
+
+// Service issues and verifies auth codes
+type Service struct {
+	store  Store
+	sender Sender
+	cfg    Config
+	now    func() time.Time
+}
+
+// NewService creates an auth code service.
+// Secret is required: without it the 6-digit codes in a leaked store can be brute forced offline.
+func NewService(store Store, sender Sender, cfg Config) (*Service, error) {
+	if len(cfg.Secret) == 0 {
+		return nil, fmt.Errorf("auth code secret is required")
+	}
+	if cfg.CodeLength <= 0 {
+		cfg.CodeLength = DefaultConfig.CodeLength
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultConfig.TTL
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultConfig.MaxAttempts
+	}
+	if cfg.ResendInterval <= 0 {
+		cfg.ResendInterval = DefaultConfig.ResendInterval
+	}
+	if cfg.MaxSends <= 0 {
+		cfg.MaxSends = DefaultConfig.MaxSends
+	}
+	if cfg.SendWindow <= 0 {
+		cfg.SendWindow = DefaultConfig.SendWindow
+	}
+	return &Service{store: store, sender: sender, cfg: cfg, now: time.Now}, nil
+}
+
+// Send generates a new code, sends it and stores its hash.
+// A previous code for the same email and purpose stops working.
+// A failed delivery does not count towards the resend interval or the send limit.
+func (s *Service) Send(ctx context.Context, email, purpose string) error {
+	email = normalizeEmail(email)
+	now := s.now()
+	code, err := generateCode(s.cfg.CodeLength)
+	if err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to generate auth code", string(_error.ErrFromInternal))
+	}
+
+	// 동시 요청이 제한을 넘지 않도록 저장소에서 원자적으로 발송을 예약합니다.
+	wait, err := s.store.ReserveSend(ctx, email, purpose, now, SendLimits{
+		ResendInterval: s.cfg.ResendInterval,
+		MaxSends:       s.cfg.MaxSends,
+		Window:         s.cfg.SendWindow,
+	})
+	switch {
+	case errors.Is(err, ErrResendTooSoon), errors.Is(err, ErrSendLimitReached):
+		return tooManyRequests(ctx, err.Error(), wait)
+	case err != nil:
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to reserve auth code send", string(_error.ErrFromInternal))
+	}
+
+	if err := s.sender.SendCode(ctx, email, purpose, code); err != nil {
+		// 발송에 실패하면 예약을 되돌려 재발송 간격과 발송 횟수를 소모하지 않습니다.
+		if releaseErr := s.store.ReleaseSend(ctx, email, purpose, now.Unix()); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to send auth code", string(_error.ErrFromAws))
+	}
+
+	if err := s.store.SaveCode(ctx, email, purpose, s.hash(email, purpose, code), now.Add(s.cfg.TTL).Unix(), s.cfg.TTL); err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to save auth code", string(_error.ErrFromInternal))
+	}
+	return nil
+}
+
+// Verify checks the code and invalidates it on success.
+// It returns CODE_NOT_FOUND when no valid code exists, INVALID_AUTH_CODE on a mismatch
+// and TOO_MANY_REQUESTS once MaxAttempts is reached.
+func (s *Service) Verify(ctx context.Context, email, purpose, code string) error {
+	email = normalizeEmail(email)
+	now := s.now()
+
+	record, err := s.store.Get(ctx, email, purpose)
+	if errors.Is(err, ErrRecordNotFound) {
+		return _error.CreateError(ctx, string(_error.ErrCodeNotFound), _error.Trace(), "auth code not found", string(_error.ErrFromClient))
+	}
+	if err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to get auth code", string(_error.ErrFromInternal))
+	}
+	if record.CodeHash == "" || now.Unix() >= record.ExpiresAt {
+		return _error.CreateError(ctx, string(_error.ErrCodeNotFound), _error.Trace(), "auth code not found or expired", string(_error.ErrFromClient))
+	}
+
+	// 동시 요청이 한도를 넘지 않도록 비교 전에 시도 횟수를 먼저 올립니다.
+	attempts, err := s.store.IncrementAttempts(ctx, email, purpose)
+	if errors.Is(err, ErrRecordNotFound) {
+		return _error.CreateError(ctx, string(_error.ErrCodeNotFound), _error.Trace(), "auth code not found or expired", string(_error.ErrFromClient))
+	}
+	if err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to record auth code attempt", string(_error.ErrFromInternal))
+	}
+	if attempts > s.cfg.MaxAttempts {
+		return tooManyRequests(ctx, "auth code attempt limit exceeded", time.Unix(record.ExpiresAt, 0).Sub(now))
+	}
+
+	codeHash := s.hash(email, purpose, strings.TrimSpace(code))
+	if !hmac.Equal([]byte(record.CodeHash), []byte(codeHash)) {
+		return _error.NewAppError(ctx, string(_error.ErrInvalidAuthCode), _error.Trace(), "invalid auth code", string(_error.ErrFromClient)).
+			WithDetail("remainingAttempts", s.cfg.MaxAttempts-attempts)
+	}
+
+	// 사용한 코드는 재사용할 수 없도록 해시가 그대로일 때만 지웁니다. 발송 제한 정보는 유지합니다.
+	consumed, err := s.store.Consume(ctx, email, purpose, codeHash)
+	if err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to consume auth code", string(_error.ErrFromInternal))
+	}
+	if !consumed {
+		return _error.CreateError(ctx, string(_error.ErrCodeNotFound), _error.Trace(), "auth code already used", string(_error.ErrFromClient))
+	}
+	return nil
+}
+
+// hash returns the HMAC-SHA256 of the code bound to the email and purpose
+func (s *Service) hash(email, purpose, code string) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte(purpose + ":" + email + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateCode returns a uniformly random numeric code
+func generateCode(length int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(byte('0' + n.Int64()))
+	}
+	return sb.String(), nil
+}
+
+// normalizeEmail lowercases and trims the email so limits apply per address
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// tooManyRequests returns a TOO_MANY_REQUESTS error with the retry delay in seconds
+func tooManyRequests(ctx context.Context, msg string, wait time.Duration) error {
+	retryAfter := int(wait.Seconds())
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return _error.NewAppError(ctx, string(_error.ErrTooManyRequest), _error.Trace(), fmt.Sprintf("%s, retry after %ds", msg, retryAfter), string(_error.ErrFromClient)).
+		WithDetail("retryAfter", retryAfter)
+}
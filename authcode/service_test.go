@@ -0,0 +1,334 @@
+package authcode
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+// memStore is an in-memory Store for tests
+type memStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func newMemStore() *memStore {
+	return &memStore{records: map[string]Record{}}
+}
+
+func (m *memStore) Get(ctx context.Context, email, purpose string) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[purpose+":"+email]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+	return &record, nil
+}
+
+func (m *memStore) ReserveSend(ctx context.Context, email, purpose string, now time.Time, limits SendLimits) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[purpose+":"+email]
+	if !ok {
+		record = Record{Email: email, Purpose: purpose}
+	}
+	wait, err := reserveSend(&record, now, limits)
+	if err != nil {
+		return wait, err
+	}
+	m.records[purpose+":"+email] = record
+	return 0, nil
+}
+
+func (m *memStore) ReleaseSend(ctx context.Context, email, purpose string, sentAt int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record := m.records[purpose+":"+email]
+	if releaseSend(&record, sentAt) {
+		m.records[purpose+":"+email] = record
+	}
+	return nil
+}
+
+func (m *memStore) SaveCode(ctx context.Context, email, purpose, codeHash string, expiresAt int64, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record := m.records[purpose+":"+email]
+	record.Email, record.Purpose = email, purpose
+	record.CodeHash, record.ExpiresAt, record.Attempts = codeHash, expiresAt, 0
+	m.records[purpose+":"+email] = record
+	return nil
+}
+
+func (m *memStore) IncrementAttempts(ctx context.Context, email, purpose string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[purpose+":"+email]
+	if !ok {
+		return 0, ErrRecordNotFound
+	}
+	record.Attempts++
+	m.records[purpose+":"+email] = record
+	return record.Attempts, nil
+}
+
+func (m *memStore) Consume(ctx context.Context, email, purpose, codeHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[purpose+":"+email]
+	if !ok || record.CodeHash != codeHash {
+		return false, nil
+	}
+	record.CodeHash = ""
+	record.Attempts = 0
+	m.records[purpose+":"+email] = record
+	return true, nil
+}
+
+// newTestService returns a service and a pointer to the last code it sent
+func newTestService(t *testing.T) (*Service, *memStore, *string) {
+	t.Helper()
+	store := newMemStore()
+	var code string
+	sender := SenderFunc(func(ctx context.Context, email, purpose, c string) error {
+		code = c
+		return nil
+	})
+	service, err := NewService(store, sender, Config{MaxAttempts: 3, Secret: []byte("secret")})
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	return service, store, &code
+}
+
+func TestNewServiceRequiresSecret(t *testing.T) {
+	sender := SenderFunc(func(ctx context.Context, email, purpose, code string) error { return nil })
+	if _, err := NewService(newMemStore(), sender, Config{}); err == nil {
+		t.Error("NewService() without secret error = nil, want error")
+	}
+}
+
+func errTypeOf(err error) string {
+	if appErr, ok := _error.AsAppError(err); ok {
+		return appErr.ErrType
+	}
+	return ""
+}
+
+func TestVerifyLimits(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name      string
+		wrong     int  // wrong codes entered before the correct one
+		expire    bool // move the clock past the TTL
+		reuse     bool // verify the correct code twice
+		wantErr   _error.ErrType
+		remaining int // remainingAttempts of the last wrong code
+	}{
+		{name: "correct code", wantErr: ""},
+		{name: "wrong codes below the limit", wrong: 2, wantErr: "", remaining: 1},
+		{name: "wrong codes up to the limit", wrong: 3, wantErr: _error.ErrTooManyRequest, remaining: 0},
+		{name: "expired code", expire: true, wantErr: _error.ErrCodeNotFound},
+		{name: "used code", reuse: true, wantErr: _error.ErrCodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, code := newTestService(t)
+			if err := service.Send(ctx, "User@Example.com", "signup"); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+			for i := 0; i < tt.wrong; i++ {
+				err := service.Verify(ctx, "user@example.com", "signup", "wrong")
+				if got := errTypeOf(err); got != string(_error.ErrInvalidAuthCode) {
+					t.Fatalf("wrong code %d: error type = %q, want %q", i+1, got, _error.ErrInvalidAuthCode)
+				}
+				if i == tt.wrong-1 {
+					appErr, _ := _error.AsAppError(err)
+					if got := appErr.Details["remainingAttempts"]; got != tt.remaining {
+						t.Errorf("remainingAttempts = %v, want %d", got, tt.remaining)
+					}
+				}
+			}
+			if tt.expire {
+				service.now = func() time.Time { return time.Now().Add(DefaultConfig.TTL) }
+			}
+			if tt.reuse {
+				if err := service.Verify(ctx, "user@example.com", "signup", *code); err != nil {
+					t.Fatalf("first Verify() error = %v", err)
+				}
+			}
+
+			err := service.Verify(ctx, "user@example.com", "signup", *code)
+			if got := errTypeOf(err); got != string(tt.wantErr) {
+				t.Errorf("Verify() error = %v, want type %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyConcurrentUseOnce(t *testing.T) {
+	ctx := context.Background()
+	service, _, code := newTestService(t)
+	if err := service.Send(ctx, "user@example.com", "signup"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- service.Verify(ctx, "user@example.com", "signup", *code)
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+		} else if !errors.Is(err, _error.ErrCodeNotFound) && !errors.Is(err, _error.ErrTooManyRequest) {
+			t.Errorf("Verify() unexpected error = %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Errorf("successful verifications = %d, want 1", succeeded)
+	}
+}
+
+func TestVerifyConcurrentAttemptLimit(t *testing.T) {
+	ctx := context.Background()
+	service, store, _ := newTestService(t)
+	if err := service.Send(ctx, "user@example.com", "signup"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	compared := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := service.Verify(ctx, "user@example.com", "signup", "wrong")
+			if errors.Is(err, _error.ErrInvalidAuthCode) {
+				mu.Lock()
+				compared++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if compared != service.cfg.MaxAttempts {
+		t.Errorf("compared codes = %d, want %d", compared, service.cfg.MaxAttempts)
+	}
+	record, _ := store.Get(ctx, "user@example.com", "signup")
+	if record.Attempts != 20 {
+		t.Errorf("attempts = %d, want 20", record.Attempts)
+	}
+}
+
+func TestSendLimits(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		sendAt   []time.Duration // offsets from start of the successful sends before the checked one
+		failing  bool            // the sender fails once before the checked send
+		checkAt  time.Duration
+		wantErr  _error.ErrType
+		wantWait int // retryAfter in seconds
+	}{
+		{name: "first send", checkAt: 0},
+		{name: "resend too soon", sendAt: []time.Duration{0}, checkAt: 20 * time.Second, wantErr: _error.ErrTooManyRequest, wantWait: 40},
+		{name: "resend after the interval", sendAt: []time.Duration{0}, checkAt: time.Minute},
+		{name: "send limit reached", sendAt: []time.Duration{0, time.Minute, 2 * time.Minute}, checkAt: 3 * time.Minute, wantErr: _error.ErrTooManyRequest, wantWait: 57 * 60},
+		{name: "new send window", sendAt: []time.Duration{0, time.Minute, 2 * time.Minute}, checkAt: time.Hour},
+		{name: "failed send does not use the interval", failing: true, checkAt: time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMemStore()
+			fail := false
+			sender := SenderFunc(func(ctx context.Context, email, purpose, code string) error {
+				if fail {
+					return errors.New("ses unavailable")
+				}
+				return nil
+			})
+			service, err := NewService(store, sender, Config{MaxSends: 3, Secret: []byte("secret")})
+			if err != nil {
+				t.Fatalf("NewService() error = %v", err)
+			}
+			for _, offset := range tt.sendAt {
+				service.now = func() time.Time { return start.Add(offset) }
+				if err := service.Send(ctx, "user@example.com", "signup"); err != nil {
+					t.Fatalf("Send() at %v error = %v", offset, err)
+				}
+			}
+			if tt.failing {
+				fail = true
+				service.now = func() time.Time { return start }
+				if err := service.Send(ctx, "user@example.com", "signup"); !errors.Is(err, _error.ErrInternalServer) {
+					t.Fatalf("failed Send() error = %v, want %s", err, _error.ErrInternalServer)
+				}
+				if record, err := store.Get(ctx, "user@example.com", "signup"); err == nil && (record.CodeHash != "" || record.SendCount != 0) {
+					t.Fatalf("record after failed send = %+v, want no code and no sends", record)
+				}
+				fail = false
+			}
+
+			service.now = func() time.Time { return start.Add(tt.checkAt) }
+			err = service.Send(ctx, "user@example.com", "signup")
+			if got := errTypeOf(err); got != string(tt.wantErr) {
+				t.Fatalf("Send() error = %v, want type %q", err, tt.wantErr)
+			}
+			if tt.wantErr != "" {
+				appErr, _ := _error.AsAppError(err)
+				if got := appErr.Details["retryAfter"]; got != tt.wantWait {
+					t.Errorf("retryAfter = %v, want %d", got, tt.wantWait)
+				}
+			}
+		})
+	}
+}
+
+func TestSendConcurrent(t *testing.T) {
+	ctx := context.Background()
+	service, store, _ := newTestService(t)
+
+	var wg sync.WaitGroup
+	results := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- service.Send(ctx, "user@example.com", "signup")
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	sent := 0
+	for err := range results {
+		if err == nil {
+			sent++
+		} else if !errors.Is(err, _error.ErrTooManyRequest) {
+			t.Errorf("Send() unexpected error = %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("successful sends = %d, want 1", sent)
+	}
+	if record, _ := store.Get(ctx, "user@example.com", "signup"); record.SendCount != 1 {
+		t.Errorf("send count = %d, want 1", record.SendCount)
+	}
+}
@@ -0,0 +1,119 @@
+package authcode
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/JokerTrickster/common/db/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormStore stores records in the mysql.UserAuths table (Type holds the purpose)
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore creates a MySQL-backed store
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+func (s *GormStore) Get(ctx context.Context, email, purpose string) (*Record, error) {
+	var row mysql.UserAuths
+	err := s.db.WithContext(ctx).Where("email = ? AND type = ?", email, purpose).Order("id DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Record{
+		Email:           row.Email,
+		Purpose:         row.Type,
+		CodeHash:        row.AuthCode,
+		ExpiresAt:       row.ExpiresAt,
+		Attempts:        row.Attempts,
+		SendCount:       row.SendCount,
+		LastSentAt:      row.LastSentAt,
+		WindowStartedAt: row.WindowStartedAt,
+	}, nil
+}
+
+// ReserveSend locks the latest row and applies the limits inside a transaction
+func (s *GormStore) ReserveSend(ctx context.Context, email, purpose string, now time.Time, limits SendLimits) (time.Duration, error) {
+	var wait time.Duration
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row, err := latestRowForUpdate(tx, email, purpose)
+		if err != nil {
+			return err
+		}
+		record := Record{SendCount: row.SendCount, LastSentAt: row.LastSentAt, WindowStartedAt: row.WindowStartedAt}
+		if wait, err = reserveSend(&record, now, limits); err != nil {
+			return err
+		}
+		row.SendCount = record.SendCount
+		row.LastSentAt = record.LastSentAt
+		row.WindowStartedAt = record.WindowStartedAt
+		return tx.Save(row).Error
+	})
+	return wait, err
+}
+
+func (s *GormStore) ReleaseSend(ctx context.Context, email, purpose string, sentAt int64) error {
+	return s.db.WithContext(ctx).Model(&mysql.UserAuths{}).
+		Where("email = ? AND type = ? AND last_sent_at = ? AND send_count > 0", email, purpose, sentAt).
+		Updates(map[string]interface{}{"send_count": gorm.Expr("send_count - 1"), "last_sent_at": 0}).Error
+}
+
+// SaveCode updates the latest row; ttl is not used because rows are kept for auditing
+func (s *GormStore) SaveCode(ctx context.Context, email, purpose, codeHash string, expiresAt int64, ttl time.Duration) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row, err := latestRowForUpdate(tx, email, purpose)
+		if err != nil {
+			return err
+		}
+		row.AuthCode = codeHash
+		row.ExpiresAt = expiresAt
+		row.Attempts = 0
+		return tx.Save(row).Error
+	})
+}
+
+// latestRowForUpdate locks the latest row of the email and purpose, or returns a new row
+func latestRowForUpdate(tx *gorm.DB, email, purpose string) (*mysql.UserAuths, error) {
+	var row mysql.UserAuths
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("email = ? AND type = ?", email, purpose).Order("id DESC").First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &mysql.UserAuths{Email: email, Type: purpose}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+func (s *GormStore) IncrementAttempts(ctx context.Context, email, purpose string) (int, error) {
+	var row mysql.UserAuths
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&mysql.UserAuths{}).
+			Where("email = ? AND type = ?", email, purpose).
+			Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Where("email = ? AND type = ?", email, purpose).Order("id DESC").First(&row).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrRecordNotFound
+	}
+	return row.Attempts, err
+}
+
+func (s *GormStore) Consume(ctx context.Context, email, purpose, codeHash string) (bool, error) {
+	result := s.db.WithContext(ctx).Model(&mysql.UserAuths{}).
+		Where("email = ? AND type = ? AND auth_code = ?", email, purpose, codeHash).
+		Updates(map[string]interface{}{"auth_code": "", "attempts": 0})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,180 @@
+package authcode
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStore stores records as Redis hashes under auth:code:<purpose>:<email>
+type RedisStore struct {
+	service *_redis.RedisService
+}
+
+// NewRedisStore creates a Redis-backed store
+func NewRedisStore(service *_redis.RedisService) *RedisStore {
+	return &RedisStore{service: service}
+}
+
+func (s *RedisStore) Get(ctx context.Context, email, purpose string) (*Record, error) {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := client.HGetAll(ctx, redisKey(email, purpose)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, ErrRecordNotFound
+	}
+	return &Record{
+		Email:           email,
+		Purpose:         purpose,
+		CodeHash:        fields["codeHash"],
+		ExpiresAt:       parseInt(fields["expiresAt"]),
+		Attempts:        int(parseInt(fields["attempts"])),
+		SendCount:       int(parseInt(fields["sendCount"])),
+		LastSentAt:      parseInt(fields["lastSentAt"]),
+		WindowStartedAt: parseInt(fields["windowStartedAt"]),
+	}, nil
+}
+
+// reserveSendScript mirrors reserveSend so the limits are checked and updated atomically.
+// It returns {0, 0} on success, or {1|2, seconds to wait} for ErrResendTooSoon / ErrSendLimitReached.
+var reserveSendScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local maxSends = tonumber(ARGV[3])
+local window = tonumber(ARGV[4])
+local fields = redis.call("HMGET", KEYS[1], "sendCount", "lastSentAt", "windowStartedAt")
+local sendCount = tonumber(fields[1]) or 0
+local lastSentAt = tonumber(fields[2]) or 0
+local windowStartedAt = tonumber(fields[3]) or 0
+if lastSentAt > 0 and lastSentAt + interval > now then
+	return {1, lastSentAt + interval - now}
+end
+if windowStartedAt == 0 or now - windowStartedAt >= window then
+	sendCount = 0
+	windowStartedAt = now
+end
+if sendCount >= maxSends then
+	return {2, windowStartedAt + window - now}
+end
+redis.call("HSET", KEYS[1], "sendCount", sendCount + 1, "lastSentAt", ARGV[1], "windowStartedAt", windowStartedAt)
+local ttl = windowStartedAt + window - now
+if redis.call("TTL", KEYS[1]) < ttl then
+	redis.call("EXPIRE", KEYS[1], ttl)
+end
+return {0, 0}
+`)
+
+func (s *RedisStore) ReserveSend(ctx context.Context, email, purpose string, now time.Time, limits SendLimits) (time.Duration, error) {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	result, err := reserveSendScript.Run(ctx, client, []string{redisKey(email, purpose)},
+		now.Unix(), int64(limits.ResendInterval.Seconds()), limits.MaxSends, int64(limits.Window.Seconds()),
+	).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	wait := time.Duration(result[1]) * time.Second
+	switch result[0] {
+	case 1:
+		return wait, ErrResendTooSoon
+	case 2:
+		return wait, ErrSendLimitReached
+	}
+	return 0, nil
+}
+
+// releaseSendScript mirrors releaseSend
+var releaseSendScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "lastSentAt") == ARGV[1] then
+	redis.call("HINCRBY", KEYS[1], "sendCount", -1)
+	redis.call("HSET", KEYS[1], "lastSentAt", 0)
+end
+return 0
+`)
+
+func (s *RedisStore) ReleaseSend(ctx context.Context, email, purpose string, sentAt int64) error {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	return releaseSendScript.Run(ctx, client, []string{redisKey(email, purpose)}, sentAt).Err()
+}
+
+// saveCodeScript stores the code and extends the TTL of the record when it is shorter than ARGV[3]
+var saveCodeScript = redis.NewScript(`
+redis.call("HSET", KEYS[1], "codeHash", ARGV[1], "expiresAt", ARGV[2], "attempts", 0)
+if redis.call("TTL", KEYS[1]) < tonumber(ARGV[3]) then
+	redis.call("EXPIRE", KEYS[1], ARGV[3])
+end
+return 0
+`)
+
+func (s *RedisStore) SaveCode(ctx context.Context, email, purpose, codeHash string, expiresAt int64, ttl time.Duration) error {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	return saveCodeScript.Run(ctx, client, []string{redisKey(email, purpose)}, codeHash, expiresAt, int64(ttl.Seconds())).Err()
+}
+
+// incrementAttemptsScript increments attempts only if the record still exists,
+// so an expired record is not recreated without a TTL
+var incrementAttemptsScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 0 then
+	return -1
+end
+return redis.call("HINCRBY", KEYS[1], "attempts", 1)
+`)
+
+func (s *RedisStore) IncrementAttempts(ctx context.Context, email, purpose string) (int, error) {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+	attempts, err := incrementAttemptsScript.Run(ctx, client, []string{redisKey(email, purpose)}).Int()
+	if err != nil {
+		return 0, err
+	}
+	if attempts < 0 {
+		return 0, ErrRecordNotFound
+	}
+	return attempts, nil
+}
+
+// consumeScript clears the code only if the stored hash matches
+var consumeScript = redis.NewScript(`
+if redis.call("HGET", KEYS[1], "codeHash") == ARGV[1] then
+	redis.call("HSET", KEYS[1], "codeHash", "", "attempts", 0)
+	return 1
+end
+return 0
+`)
+
+func (s *RedisStore) Consume(ctx context.Context, email, purpose, codeHash string) (bool, error) {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return false, err
+	}
+	consumed, err := consumeScript.Run(ctx, client, []string{redisKey(email, purpose)}, codeHash).Int()
+	return consumed == 1, err
+}
+
+// redisKey returns the hash key for an email and purpose
+func redisKey(email, purpose string) string {
+	return _redis.AuthCodeKey + purpose + ":" + email
+}
+
+func parseInt(value string) int64 {
+	n, _ := strconv.ParseInt(value, 10, 64)
+	return n
+}
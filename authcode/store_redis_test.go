@@ -0,0 +1,138 @@
+package authcode
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestRedisStore returns a store backed by an in-process Redis server
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	service := &_redis.RedisService{}
+	if err := service.Initialize(context.Background(), "redis://"+server.Addr()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return NewRedisStore(service), server
+}
+
+var testLimits = SendLimits{ResendInterval: time.Minute, MaxSends: 2, Window: time.Hour}
+
+func TestRedisStoreReserveSend(t *testing.T) {
+	ctx := context.Background()
+	start := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		sendAt   []time.Duration // offsets of earlier reservations
+		release  bool            // release the last earlier reservation
+		checkAt  time.Duration
+		wantErr  error
+		wantWait time.Duration
+	}{
+		{name: "first send"},
+		{name: "resend too soon", sendAt: []time.Duration{0}, checkAt: 20 * time.Second, wantErr: ErrResendTooSoon, wantWait: 40 * time.Second},
+		{name: "released send", sendAt: []time.Duration{0}, release: true, checkAt: time.Second},
+		{name: "send limit reached", sendAt: []time.Duration{0, time.Minute}, checkAt: 2 * time.Minute, wantErr: ErrSendLimitReached, wantWait: 58 * time.Minute},
+		{name: "new send window", sendAt: []time.Duration{0, time.Minute}, checkAt: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, server := newTestRedisStore(t)
+			for _, offset := range tt.sendAt {
+				if _, err := store.ReserveSend(ctx, "user@example.com", "signup", start.Add(offset), testLimits); err != nil {
+					t.Fatalf("ReserveSend() at %v error = %v", offset, err)
+				}
+			}
+			if tt.release {
+				sentAt := start.Add(tt.sendAt[len(tt.sendAt)-1]).Unix()
+				if err := store.ReleaseSend(ctx, "user@example.com", "signup", sentAt); err != nil {
+					t.Fatalf("ReleaseSend() error = %v", err)
+				}
+			}
+
+			wait, err := store.ReserveSend(ctx, "user@example.com", "signup", start.Add(tt.checkAt), testLimits)
+			if !errors.Is(err, tt.wantErr) || wait != tt.wantWait {
+				t.Fatalf("ReserveSend() = %v, %v, want %v, %v", wait, err, tt.wantWait, tt.wantErr)
+			}
+			if ttl := server.TTL(redisKey("user@example.com", "signup")); ttl <= 0 || ttl > testLimits.Window {
+				t.Errorf("TTL = %v, want up to the send window", ttl)
+			}
+		})
+	}
+}
+
+func TestRedisStoreReserveSendConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestRedisStore(t)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.ReserveSend(ctx, "user@example.com", "signup", now, testLimits)
+			if err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrResendTooSoon) {
+				t.Errorf("ReserveSend() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != 1 {
+		t.Errorf("successful reservations = %d, want 1", reserved)
+	}
+}
+
+func TestRedisStoreCode(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestRedisStore(t)
+	now := time.Now()
+	if _, err := store.ReserveSend(ctx, "user@example.com", "signup", now, testLimits); err != nil {
+		t.Fatalf("ReserveSend() error = %v", err)
+	}
+	if err := store.SaveCode(ctx, "user@example.com", "signup", "hash", now.Add(5*time.Minute).Unix(), 5*time.Minute); err != nil {
+		t.Fatalf("SaveCode() error = %v", err)
+	}
+	key := redisKey("user@example.com", "signup")
+	if ttl := server.TTL(key); ttl != testLimits.Window {
+		t.Errorf("TTL after SaveCode = %v, want the send window %v", ttl, testLimits.Window)
+	}
+
+	record, err := store.Get(ctx, "user@example.com", "signup")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.CodeHash != "hash" || record.SendCount != 1 || record.LastSentAt != now.Unix() {
+		t.Errorf("Get() = %+v, want the saved code and one send", record)
+	}
+	if attempts, err := store.IncrementAttempts(ctx, "user@example.com", "signup"); attempts != 1 || err != nil {
+		t.Errorf("IncrementAttempts() = %d, %v, want 1, nil", attempts, err)
+	}
+	if consumed, err := store.Consume(ctx, "user@example.com", "signup", "hash"); !consumed || err != nil {
+		t.Errorf("Consume() = %v, %v, want true, nil", consumed, err)
+	}
+	if consumed, err := store.Consume(ctx, "user@example.com", "signup", "hash"); consumed || err != nil {
+		t.Errorf("second Consume() = %v, %v, want false, nil", consumed, err)
+	}
+
+	server.FastForward(testLimits.Window)
+	if _, err := store.IncrementAttempts(ctx, "user@example.com", "signup"); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("IncrementAttempts() after expiry error = %v, want %v", err, ErrRecordNotFound)
+	}
+	if server.Exists(key) {
+		t.Error("IncrementAttempts() recreated the expired record")
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	AwsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sesv2"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
@@ -19,6 +20,7 @@ type AWSService struct {
 	initOnce      sync.Once
 	ssmClient     *ssm.Client
 	sesClient     *sesv2.Client
+	kmsClient     *kms.Client
 	s3Client      *s3.Client
 	uploader      *manager.Uploader
 	downloader    *manager.Downloader
@@ -67,6 +69,17 @@ func (a *AWSService) GetSESClient() (*sesv2.Client, error) {
 	return a.sesClient, nil
 }
 
+// GetKMSClient lazily initializes and returns the KMS client
+func (a *AWSService) GetKMSClient() (*kms.Client, error) {
+	if a.kmsClient == nil {
+		if err := a.Initialize(); err != nil {
+			return nil, err
+		}
+		a.kmsClient = kms.NewFromConfig(a.awsConfig)
+	}
+	return a.kmsClient, nil
+}
+
 // GetS3Client lazily initializes and returns the S3 client
 func (a *AWSService) GetS3Client() (*s3.Client, error) {
 	if a.s3Client == nil {
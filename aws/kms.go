@@ -0,0 +1,56 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// KMSService generates and decrypts data keys with an AWS KMS master key.
+// It implements jwt.KeyProvider for envelope encryption.
+type KMSService struct {
+	service *AWSService
+	keyID   string
+}
+
+// GetKMSService creates a KMSService for the master key ID, ARN or alias
+func GetKMSService(region, keyID string) *KMSService {
+	return &KMSService{service: GetAWSService(region), keyID: keyID}
+}
+
+// GenerateDataKey returns a new AES-256 data key in plaintext and encrypted form
+func (s *KMSService) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	client, err := s.service.GetKMSClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize KMS client: %w", err)
+	}
+
+	out, err := client.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:   aws.String(s.keyID),
+		KeySpec: types.DataKeySpecAes256,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	return out.Plaintext, out.CiphertextBlob, nil
+}
+
+// DecryptDataKey decrypts a data key returned by GenerateDataKey
+func (s *KMSService) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	client, err := s.service.GetKMSClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize KMS client: %w", err)
+	}
+
+	out, err := client.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob: encrypted,
+		KeyId:          aws.String(s.keyID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+	return out.Plaintext, nil
+}
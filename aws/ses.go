@@ -3,6 +3,7 @@ package aws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -37,6 +38,13 @@ type EmailService struct {
 
 var emailService *EmailService
 
+var (
+	// ErrEmailNotInitialized is returned when InitAwsSes was not called
+	ErrEmailNotInitialized = errors.New("email service is not initialized")
+	// ErrEmailQueueFull is returned when the email queue has no free slot
+	ErrEmailQueueFull = errors.New("email queue is full")
+)
+
 // Initialize the EmailService
 func InitAwsSes(client *sesv2.Client, from string, bufferSize int) {
 	emailService = &EmailService{
@@ -90,10 +98,21 @@ func (s *EmailService) send(email EmailTemplate) error {
 
 // Send email (push to channel)
 func (s *EmailService) QueueEmail(email EmailTemplate) {
+	if err := s.TryQueueEmail(email); err != nil {
+		log.Printf("Dropping email: %v", err)
+	}
+}
+
+// TryQueueEmail pushes the email to the channel and reports why it could not
+func (s *EmailService) TryQueueEmail(email EmailTemplate) error {
+	if s == nil {
+		return ErrEmailNotInitialized
+	}
 	select {
 	case s.mailReq <- email:
+		return nil
 	default:
-		log.Println("Email queue is full. Dropping email.")
+		return ErrEmailQueueFull
 	}
 }
 
@@ -123,6 +142,18 @@ func EmailSendAuthCode(email, validateCode string) {
 	})
 }
 
+// EmailSendAuthCodeTemplate queues an auth code email with the given SES template
+func EmailSendAuthCodeTemplate(templateName, email, validateCode string) error {
+	return emailService.TryQueueEmail(EmailTemplate{
+		Name:       templateName,
+		Recipients: []string{email},
+		Data: map[string]interface{}{
+			"code": validateCode,
+		},
+		Type: emailTypeAuth,
+	})
+}
+
 type ReqReportSES struct {
 	UserID string
 	Reason string
@@ -11,6 +11,7 @@ func MigrateSchema(db *gorm.DB) error {
 	err := db.AutoMigrate(
 		&Tokens{},
 		&Users{},
+		&UserIdentities{},
 		&Foods{},
 		&Categories{},
 		&FoodCategories{},
@@ -8,18 +8,33 @@ type Tokens struct {
 	AccessToken      string `json:"accessToken" gorm:"column:access_token"`
 	RefreshToken     string `json:"refreshToken" gorm:"column:refresh_token"`
 	RefreshExpiredAt int64  `json:"refreshExpiredAt" gorm:"column:refresh_expired_at"`
+	TokenID          string `json:"tokenID" gorm:"column:token_id;index"`
+	FamilyID         string `json:"familyID" gorm:"column:family_id;index"`
+	Used             bool   `json:"used" gorm:"column:used"`
+	Revoked          bool   `json:"revoked" gorm:"column:revoked"`
 }
 
 type Users struct {
 	gorm.Model
+	Email         string `json:"email" gorm:"column:email"`
+	EmailVerified bool   `json:"emailVerified" gorm:"column:email_verified;default:false"`
+	Password      string `json:"password" gorm:"column:password"`
+	Birth         string `json:"birth" gorm:"column:birth"`
+	Name          string `json:"name" gorm:"column:name"`
+	Sex           string `json:"sex" gorm:"column:sex"`
+	Provider      string `json:"provider" gorm:"column:provider"`
+	Push          *bool  `json:"push" gorm:"column:push"`
+	Image         string `json:"image" gorm:"column:image"`
+}
+
+// UserIdentities links OAuth provider accounts to a user (a user may have one per provider)
+type UserIdentities struct {
+	gorm.Model
+	UserID   uint   `json:"userID" gorm:"column:user_id;uniqueIndex:idx_user_identities_user_provider"`
+	Provider string `json:"provider" gorm:"column:provider;size:32;uniqueIndex:idx_user_identities_provider_subject;uniqueIndex:idx_user_identities_user_provider"`
+	Subject  string `json:"subject" gorm:"column:subject;size:255;uniqueIndex:idx_user_identities_provider_subject"`
 	Email    string `json:"email" gorm:"column:email"`
-	Password string `json:"password" gorm:"column:password"`
-	Birth    string `json:"birth" gorm:"column:birth"`
-	Name     string `json:"name" gorm:"column:name"`
-	Sex      string `json:"sex" gorm:"column:sex"`
-	Provider string `json:"provider" gorm:"column:provider"`
-	Push     *bool  `json:"push" gorm:"column:push"`
-	Image    string `json:"image" gorm:"column:image"`
+	LinkedAt int64  `json:"linkedAt" gorm:"column:linked_at"`
 }
 
 type MetaTables struct {
@@ -58,9 +73,14 @@ type Themes struct {
 
 type UserAuths struct {
 	gorm.Model
-	Email    string `json:"email" gorm:"column:email"`
-	AuthCode string `json:"authCode" gorm:"column:auth_code"`
-	Type     string `json:"type" gorm:"column:type"`
+	Email           string `json:"email" gorm:"column:email;index:idx_user_auths_email_type"`
+	AuthCode        string `json:"authCode" gorm:"column:auth_code"` // hashed by authcode.Service
+	Type            string `json:"type" gorm:"column:type;index:idx_user_auths_email_type"`
+	ExpiresAt       int64  `json:"expiresAt" gorm:"column:expires_at"`
+	Attempts        int    `json:"attempts" gorm:"column:attempts"`
+	SendCount       int    `json:"sendCount" gorm:"column:send_count"`
+	LastSentAt      int64  `json:"lastSentAt" gorm:"column:last_sent_at"`
+	WindowStartedAt int64  `json:"windowStartedAt" gorm:"column:window_started_at"`
 }
 
 type FoodImages struct {
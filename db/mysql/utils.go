@@ -1,12 +1,18 @@
 package mysql
 
 import (
+	"errors"
 	"os"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// errDupEntry is the MySQL error number for a unique index violation
+const errDupEntry = 1062
+
 // getEnvOrFallback fetches an environment variable or returns a fallback value
 func getEnvOrFallback(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
@@ -45,3 +51,12 @@ func TimeStringToEpoch(t string) int64 {
 func TimeToEpoch(t time.Time) int64 {
 	return t.Unix()
 }
+
+// IsDuplicateKeyError reports whether err is a unique index violation
+func IsDuplicateKeyError(err error) bool {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	var mysqlErr *mysqldriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == errDupEntry
+}
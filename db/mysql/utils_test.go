@@ -0,0 +1,32 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "duplicate entry", err: &mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry 'google-1' for key 'idx_user_identities_provider_subject'"}, want: true},
+		{name: "wrapped duplicate entry", err: fmt.Errorf("create identity: %w", &mysqldriver.MySQLError{Number: 1062}), want: true},
+		{name: "translated by gorm", err: gorm.ErrDuplicatedKey, want: true},
+		{name: "other mysql error", err: &mysqldriver.MySQLError{Number: 1213, Message: "Deadlock found"}},
+		{name: "plain error", err: errors.New("connection refused")},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDuplicateKeyError(tt.err); got != tt.want {
+				t.Errorf("IsDuplicateKeyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
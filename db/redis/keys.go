@@ -7,3 +7,14 @@ const (
 	FoodMeta       = "food:meta:category"
 	FoodGuestKey   = "food:guest"
 )
+
+const (
+	// 인증 관련 레디스 키 (prefix)
+	AuthRefreshTokenKey  = "auth:refresh:token:"
+	AuthRefreshUsedKey   = "auth:refresh:used:"
+	AuthRefreshFamilyKey = "auth:refresh:family:"
+	AuthRevokedTokenKey  = "auth:revoked:token:"
+	AuthRevokedUserKey   = "auth:revoked:user:"
+	AuthCodeKey          = "auth:code:"
+	AuthOAuthStateKey    = "auth:oauth:state:"
+)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/JokerTrickster/common/auth"
 	"github.com/labstack/echo/v4"
 )
 
@@ -20,15 +21,22 @@ type CtxValues struct {
 	Email     string
 }
 
-// CtxGenerate creates a context with custom values from Echo context
+// ctxValuesKey is the context key for *CtxValues
+type ctxValuesKey struct{}
+
+// CtxGenerate creates a context with custom values from Echo context.
+// The returned context also carries the auth.Principal set by jwt.TokenChecker.
 func CtxGenerate(c echo.Context) (context.Context, uint, string) {
-	userID, _ := c.Get("uID").(uint)
+	var userID uint
+	var email string
+	if principal, ok := auth.FromEcho(c); ok {
+		userID, email = principal.UserID, principal.Email
+	}
 	requestID, _ := c.Get("rID").(string)
 	startTime, _ := c.Get("startTime").(time.Time)
-	email, _ := c.Get("email").(string)
 	req := c.Request()
 
-	ctx := context.WithValue(req.Context(), "key", &CtxValues{
+	ctx := context.WithValue(req.Context(), ctxValuesKey{}, &CtxValues{
 		Method:    req.Method,
 		Url:       req.URL.Path,
 		UserID:    userID,
@@ -38,3 +46,9 @@ func CtxGenerate(c echo.Context) (context.Context, uint, string) {
 	})
 	return ctx, userID, email
 }
+
+// GetCtxValues returns the values stored by CtxGenerate
+func GetCtxValues(ctx context.Context) (*CtxValues, bool) {
+	values, ok := ctx.Value(ctxValuesKey{}).(*CtxValues)
+	return values, ok
+}
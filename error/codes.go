@@ -35,6 +35,9 @@ const (
 	ErrInternalServer = ErrType("INTERNAL_SERVER")
 	ErrInternalDB     = ErrType("INTERNAL_DB")
 	ErrPartner        = ErrType("PARTNER")
+	ErrBadRequest     = ErrType("BAD_REQUEST")
+	ErrForbidden      = ErrType("FORBIDDEN")
+	ErrTooManyRequest = ErrType("TOO_MANY_REQUESTS")
 
 	// Auth errors
 	ErrCodeNotFound           = ErrType("CODE_NOT_FOUND")
@@ -45,29 +48,89 @@ const (
 	ErrPasswordNotMatch       = ErrType("PASSWORD_NOT_MATCH")
 	ErrInvalidAuthCode        = ErrType("INVALID_AUTH_CODE")
 	ErrInvalidEmailOrPassword = ErrType("INVALID_EMAIL_OR_PASSWORD")
+	ErrInsufficientRole       = ErrType("INSUFFICIENT_ROLE")
+	ErrInsufficientScope      = ErrType("INSUFFICIENT_SCOPE")
+	ErrNotResourceOwner       = ErrType("NOT_RESOURCE_OWNER")
+	ErrRefreshTokenReused     = ErrType("REFRESH_TOKEN_REUSED")
+	ErrTokenRevoked           = ErrType("TOKEN_REVOKED")
+	ErrTokenExpired           = ErrType("TOKEN_EXPIRED")
+	ErrWeakPassword           = ErrType("WEAK_PASSWORD")
+	ErrIdentityAlreadyLinked  = ErrType("IDENTITY_ALREADY_LINKED")
+	ErrLastIdentity           = ErrType("LAST_IDENTITY")
+
+	// Game errors
+	ErrNotEnoughCard      = ErrType("NOT_ENOUGH_CARD")
+	ErrNotEnoughCondition = ErrType("NOT_ENOUGH_CONDITION")
 
 	// Food errors
 	ErrGeminiError  = ErrType("GEMINI_INTERNAL_SERVER")
 	ErrFoodNotFound = ErrType("FOOD_NOT_FOUND")
 )
 
-// ErrHttpCode maps error types to HTTP status codes
-var ErrHttpCode = map[string]int{
-	"PARAM_BAD":                 http.StatusBadRequest,
-	"USER_ALREADY_EXISTED":      http.StatusBadRequest,
-	"BAD_REQUEST":               http.StatusBadRequest,
-	"USER_NOT_FOUND":            http.StatusBadRequest,
-	"NOT_ENOUGH_CARD":           http.StatusBadRequest,
-	"NOT_ENOUGH_CONDITION":      http.StatusBadRequest,
-	"PASSWORD_NOT_MATCH":        http.StatusBadRequest,
-	"INVALID_EMAIL_OR_PASSWORD": http.StatusBadRequest,
-	"FOOD_NOT_FOUND":            http.StatusBadRequest,
-	"TOKEN_BAD":                 http.StatusUnauthorized,
-	"INVALID_ACCESS_TOKEN":      http.StatusUnauthorized,
-	"INVALID_AUTH_CODE":         http.StatusUnauthorized,
-	"PARTNER":                   http.StatusForbidden,
-	"NOT_FOUND":                 http.StatusNotFound,
-	"INTERNAL_SERVER":           http.StatusInternalServerError,
-	"INTERNAL_DB":               http.StatusInternalServerError,
-	"GEMINI_INTERNAL_SERVER":    http.StatusInternalServerError,
+// CommonErrorSpecs are the generic error types registered by default
+var CommonErrorSpecs = []ErrorSpec{
+	{Type: ErrBadParameter, HttpCode: http.StatusBadRequest, Msg: "invalid parameter"},
+	{Type: ErrBadRequest, HttpCode: http.StatusBadRequest, Msg: "bad request"},
+	{Type: ErrBadToken, HttpCode: http.StatusUnauthorized, Msg: "invalid token"},
+	{Type: ErrPartner, HttpCode: http.StatusForbidden, Msg: "partner error"},
+	{Type: ErrForbidden, HttpCode: http.StatusForbidden, Msg: "forbidden"},
+	{Type: ErrNotFound, HttpCode: http.StatusNotFound, Msg: "not found"},
+	{Type: ErrTooManyRequest, HttpCode: http.StatusTooManyRequests, Msg: "too many requests"},
+	{Type: ErrInternalServer, HttpCode: http.StatusInternalServerError, Msg: "internal server error"},
+	{Type: ErrInternalDB, HttpCode: http.StatusInternalServerError, Msg: "internal database error"},
+}
+
+// AuthErrorSpecs are the auth error types registered by default
+var AuthErrorSpecs = []ErrorSpec{
+	{Type: ErrCodeNotFound, HttpCode: http.StatusBadRequest, Msg: "auth code not found"},
+	{Type: ErrUserNotFound, HttpCode: http.StatusBadRequest, Msg: "user not found"},
+	{Type: ErrProfileNotFount, HttpCode: http.StatusBadRequest, Msg: "profile not found"},
+	{Type: ErrUserAlreadyExisted, HttpCode: http.StatusBadRequest, Msg: "user already exists"},
+	{Type: ErrPasswordNotMatch, HttpCode: http.StatusBadRequest, Msg: "password does not match"},
+	{Type: ErrInvalidEmailOrPassword, HttpCode: http.StatusBadRequest, Msg: "invalid email or password"},
+	{Type: ErrInvalidAccessToken, HttpCode: http.StatusUnauthorized, Msg: "invalid access token"},
+	{Type: ErrInvalidAuthCode, HttpCode: http.StatusUnauthorized, Msg: "invalid auth code"},
+	{Type: ErrInsufficientRole, HttpCode: http.StatusForbidden, Msg: "insufficient role"},
+	{Type: ErrInsufficientScope, HttpCode: http.StatusForbidden, Msg: "insufficient scope"},
+	{Type: ErrNotResourceOwner, HttpCode: http.StatusForbidden, Msg: "not the resource owner"},
+	{Type: ErrRefreshTokenReused, HttpCode: http.StatusUnauthorized, Msg: "refresh token reused"},
+	{Type: ErrTokenRevoked, HttpCode: http.StatusUnauthorized, Msg: "token revoked"},
+	{Type: ErrTokenExpired, HttpCode: http.StatusUnauthorized, Msg: "token expired"},
+	{Type: ErrWeakPassword, HttpCode: http.StatusBadRequest, Msg: "password does not meet the policy"},
+	{Type: ErrIdentityAlreadyLinked, HttpCode: http.StatusConflict, Msg: "identity already linked"},
+	{Type: ErrLastIdentity, HttpCode: http.StatusBadRequest, Msg: "cannot unlink the last login method"},
+}
+
+// GameErrorSpecs are the game service error types. Game services register them at startup.
+var GameErrorSpecs = []ErrorSpec{
+	{Type: ErrNotEnoughCard, HttpCode: http.StatusBadRequest, Msg: "not enough cards"},
+	{Type: ErrNotEnoughCondition, HttpCode: http.StatusBadRequest, Msg: "not enough conditions"},
+}
+
+// FoodErrorSpecs are the food service error types. Food services register them at startup.
+var FoodErrorSpecs = []ErrorSpec{
+	{Type: ErrFoodNotFound, HttpCode: http.StatusBadRequest, Msg: "food not found"},
+	{Type: ErrGeminiError, HttpCode: http.StatusInternalServerError, Msg: "gemini internal server error"},
+}
+
+func init() {
+	// 공통 및 인증 에러 코드만 기본 등록합니다. 서비스 전용 에러는 각 서비스에서 등록합니다.
+	MustRegister(CommonErrorSpecs...)
+	MustRegister(AuthErrorSpecs...)
+}
+
+// HttpCodeOf returns the HTTP status for an error type, defaulting to 500 for unknown types
+func HttpCodeOf(errType string) int {
+	if spec, ok := Lookup(ErrType(errType)); ok {
+		return spec.HttpCode
+	}
+	return http.StatusInternalServerError
+}
+
+// httpCodeOrZero returns the registered HTTP status or 0 for unknown types
+func httpCodeOrZero(errType string) int {
+	if spec, ok := Lookup(ErrType(errType)); ok {
+		return spec.HttpCode
+	}
+	return 0
 }
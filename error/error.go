@@ -2,7 +2,9 @@ package error
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"runtime"
 	"strings"
 )
@@ -28,21 +30,134 @@ type Err struct {
 	From     string `json:"from,omitempty"`
 }
 
-// ParseError parses the error string into an Err struct
+// AppError is the structured error returned by CreateError and Wrap
+type AppError struct {
+	ErrType  string                 `json:"errType,omitempty"`
+	HttpCode int                    `json:"httpCode,omitempty"`
+	Trace    string                 `json:"trace,omitempty"`
+	Msg      string                 `json:"msg,omitempty"`
+	From     string                 `json:"from,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+	Cause    error                  `json:"-"`
+}
+
+// Error keeps the legacy "type|trace|msg|from" layout so old log parsers keep working.
+// The cause is appended to the msg field so From stays the last field for ParseError.
+func (e *AppError) Error() string {
+	msg := e.Msg
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	return fmt.Sprintf("%s|%s|%s|%s", e.ErrType, e.Trace, msg, e.From)
+}
+
+// Unwrap returns the wrapped cause
+func (e *AppError) Unwrap() error {
+	return e.Cause
+}
+
+// Is reports whether target is the same error type.
+// Both ErrType values and *AppError values can be used as targets.
+func (e *AppError) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrType:
+		return e.ErrType == string(t)
+	case *AppError:
+		return t != nil && e.ErrType == t.ErrType
+	}
+	return false
+}
+
+// WithDetail attaches a key/value detail to the error and returns it
+func (e *AppError) WithDetail(key string, value interface{}) *AppError {
+	if e.Details == nil {
+		e.Details = map[string]interface{}{}
+	}
+	e.Details[key] = value
+	return e
+}
+
+// ToErr converts the error into the logging format
+func (e *AppError) ToErr() Err {
+	return Err{
+		HttpCode: e.HttpCode,
+		ErrType:  e.ErrType,
+		Msg:      e.Msg,
+		Trace:    e.Trace,
+		From:     e.From,
+	}
+}
+
+// Error lets an ErrType be used as a sentinel with errors.Is
+func (t ErrType) Error() string {
+	return string(t)
+}
+
+// ParseError parses the legacy error string into an Err struct.
+// The message may itself contain "|"; strings that do not follow the
+// legacy format are reported as an internal error carrying the raw text.
 func ParseError(data string) Err {
-	slice := strings.Split(data, "|")
+	slice := strings.SplitN(data, "|", 3)
+	if len(slice) < 3 {
+		return Err{
+			HttpCode: http.StatusInternalServerError,
+			ErrType:  string(ErrInternalServer),
+			Msg:      data,
+			From:     string(ErrFromInternal),
+		}
+	}
+	msg, from := slice[2], ""
+	if i := strings.LastIndex(slice[2], "|"); i >= 0 {
+		msg, from = slice[2][:i], slice[2][i+1:]
+	}
 	return Err{
-		HttpCode: ErrHttpCode[slice[0]],
+		HttpCode: httpCodeOrZero(slice[0]),
 		ErrType:  slice[0],
 		Trace:    slice[1],
-		Msg:      slice[2],
-		From:     slice[3],
+		Msg:      msg,
+		From:     from,
 	}
 }
 
-// CreateError formats an error with additional context information
+// CreateError creates an *AppError with additional context information
 func CreateError(ctx context.Context, errType string, trace string, msg string, from string) error {
-	return fmt.Errorf("%s|%s|%s|%s", errType, trace, msg, from)
+	return NewAppError(ctx, errType, trace, msg, from)
+}
+
+// NewAppError is like CreateError but returns the *AppError so details can be attached.
+//
+//	return _error.NewAppError(ctx, string(_error.ErrWeakPassword), _error.Trace(), msg, string(_error.ErrFromClient)).
+//		WithDetail("violations", violations)
+func NewAppError(ctx context.Context, errType string, trace string, msg string, from string) *AppError {
+	return &AppError{
+		ErrType:  errType,
+		HttpCode: httpCodeOrZero(errType),
+		Trace:    trace,
+		Msg:      msg,
+		From:     from,
+	}
+}
+
+// Wrap creates an *AppError that keeps err as its cause
+func Wrap(ctx context.Context, err error, errType string, trace string, msg string, from string) error {
+	return &AppError{
+		ErrType:  errType,
+		HttpCode: httpCodeOrZero(errType),
+		Trace:    trace,
+		Msg:      msg,
+		From:     from,
+		Cause:    err,
+	}
+}
+
+// AsAppError extracts the *AppError created by CreateError or Wrap from err.
+// Plain errors are never parsed, so their messages stay hidden from clients.
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
 }
 
 // Trace captures the function name and line number where the error occurred
@@ -55,17 +170,29 @@ func Trace() string {
 
 // GenerateHTTPErrorResponse generates a standard HTTP error response
 func GenerateHTTPErrorResponse(err error) (int, ResError) {
-	// Parse the error into an Err struct
-	parsedErr := ParseError(err.Error())
+	appErr, ok := AsAppError(err)
+	if !ok {
+		parsedErr := ParseError(err.Error())
+		appErr = &AppError{
+			ErrType:  parsedErr.ErrType,
+			HttpCode: parsedErr.HttpCode,
+			Msg:      parsedErr.Msg,
+			From:     parsedErr.From,
+		}
+	}
 
-	// Map the parsed error to an HTTP response
+	// Map the error to an HTTP response
 	resError := ResError{
-		ErrType: parsedErr.ErrType,
-		Msg:     parsedErr.Msg,
-		Trace:   parsedErr.Trace,
-		From:    parsedErr.From,
+		ErrType: appErr.ErrType,
+		Msg:     appErr.Msg,
+		Trace:   appErr.Trace,
+		From:    appErr.From,
 	}
-	return parsedErr.HttpCode, resError
+	httpCode := appErr.HttpCode
+	if httpCode == 0 {
+		httpCode = HttpCodeOf(appErr.ErrType)
+	}
+	return httpCode, resError
 }
 
 // GenerateCustomErrorResponse allows creating custom errors without using ParseError
@@ -1,8 +1,14 @@
 package error
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+
+	"github.com/JokerTrickster/common/env"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
 )
 
 /*
@@ -20,3 +26,121 @@ func HandleError(errMsg string, args ...interface{}) string {
 	// Combine error message and parameters
 	return fmt.Sprintf("Error: %s | Parameters: %s", errMsg, strings.Join(params, ", "))
 }
+
+// HTTPErrorHandler renders every error as a ResError JSON body.
+// Use it as echo.HTTPErrorHandler: e.HTTPErrorHandler = _error.HTTPErrorHandler
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	acceptLanguage := c.Request().Header.Get("Accept-Language")
+	httpCode, resError := GenerateLocalizedHTTPErrorResponse(err, acceptLanguage)
+	if isUnknownError(err) {
+		c.Logger().Errorf("unhandled error: %v", err)
+	}
+	if !isTraceVisible() {
+		resError.Trace = ""
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(httpCode)
+	} else {
+		err = c.JSON(httpCode, resError)
+	}
+	if err != nil {
+		c.Logger().Error(err)
+	}
+}
+
+// ResolveHTTPError maps any error kind to an HTTP status and ResError
+func ResolveHTTPError(err error) (int, ResError) {
+	if appErr, ok := AsAppError(err); ok {
+		return GenerateHTTPErrorResponse(appErr)
+	}
+
+	var echoErr *echo.HTTPError
+	if errors.As(err, &echoErr) {
+		errType := errTypeFromStatus(echoErr.Code)
+		return echoErr.Code, ResError{
+			ErrType: errType,
+			Msg:     fmt.Sprintf("%v", echoErr.Message),
+			From:    string(ErrFromClient),
+		}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return HttpCodeOf(string(ErrBadParameter)), ResError{
+			ErrType: string(ErrBadParameter),
+			Msg:     validationErrs.Error(),
+			From:    string(ErrFromClient),
+		}
+	}
+
+	// 내부 에러 메시지(SQL, URL, 파일 경로 등)는 클라이언트에 노출하지 않습니다.
+	return http.StatusInternalServerError, ResError{
+		ErrType: string(ErrInternalServer),
+		Msg:     internalErrorMsg,
+		From:    string(ErrFromInternal),
+	}
+}
+
+// internalErrorMsg replaces the message of unknown errors in responses
+const internalErrorMsg = "internal server error"
+
+// GenerateLocalizedHTTPErrorResponse is like ResolveHTTPError but replaces Msg
+// with the catalogue message for the language negotiated from acceptLanguage
+func GenerateLocalizedHTTPErrorResponse(err error, acceptLanguage string) (int, ResError) {
+	httpCode, resError := ResolveHTTPError(err)
+
+	var details map[string]interface{}
+	if appErr, ok := AsAppError(err); ok {
+		details = appErr.Details
+	}
+	lang := NegotiateLanguage(acceptLanguage)
+	if msg, ok := LocalizeMessage(lang, resError.ErrType, details); ok {
+		resError.Msg = msg
+	}
+	return httpCode, resError
+}
+
+// isUnknownError reports whether ResolveHTTPError hides the message of err
+func isUnknownError(err error) bool {
+	if _, ok := AsAppError(err); ok {
+		return false
+	}
+	var echoErr *echo.HTTPError
+	var validationErrs validator.ValidationErrors
+	return !errors.As(err, &echoErr) && !errors.As(err, &validationErrs)
+}
+
+// errTypeFromStatus converts an HTTP status into an error type
+func errTypeFromStatus(status int) string {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return string(ErrInternalServer)
+	case status == http.StatusBadRequest:
+		return string(ErrBadRequest)
+	case status == http.StatusUnauthorized:
+		return string(ErrBadToken)
+	case status == http.StatusForbidden:
+		return string(ErrForbidden)
+	case status == http.StatusNotFound:
+		return string(ErrNotFound)
+	}
+	// ex) 405 -> METHOD_NOT_ALLOWED
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+}
+
+// isTraceVisible reports whether traces may be exposed to clients
+func isTraceVisible() bool {
+	if env.Env.IsLocal {
+		return true
+	}
+	switch strings.ToLower(env.Env.Env) {
+	case "local", "dev":
+		return true
+	}
+	return false
+}
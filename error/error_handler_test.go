@@ -0,0 +1,57 @@
+package error
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestResolveHTTPError(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantType string
+		wantMsg  string
+	}{
+		{
+			name:     "app error",
+			err:      CreateError(ctx, string(ErrBadParameter), "trace", "invalid email", string(ErrFromClient)),
+			wantCode: http.StatusBadRequest,
+			wantType: string(ErrBadParameter),
+			wantMsg:  "invalid email",
+		},
+		{
+			name:     "wrapped app error",
+			err:      fmt.Errorf("handler: %w", CreateError(ctx, string(ErrNotFound), "trace", "user not found", string(ErrFromClient))),
+			wantCode: http.StatusNotFound,
+			wantType: string(ErrNotFound),
+			wantMsg:  "user not found",
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("dial tcp 10.0.0.1:3306: connection refused"),
+			wantCode: http.StatusInternalServerError,
+			wantType: string(ErrInternalServer),
+			wantMsg:  internalErrorMsg,
+		},
+		{
+			name:     "plain error in the legacy format",
+			err:      errors.New("BAD_PARAMETER|trace|select * from users|CLIENT"),
+			wantCode: http.StatusInternalServerError,
+			wantType: string(ErrInternalServer),
+			wantMsg:  internalErrorMsg,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, res := ResolveHTTPError(tt.err)
+			if code != tt.wantCode || res.ErrType != tt.wantType || res.Msg != tt.wantMsg {
+				t.Errorf("ResolveHTTPError() = %d %+v, want %d %s %q", code, res, tt.wantCode, tt.wantType, tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package error
+
+/*
+	에러 메시지 다국어 처리 (Accept-Language 기반)
+*/
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	LangKorean  = "ko"
+	LangEnglish = "en"
+)
+
+type messageCatalogue struct {
+	mu          sync.RWMutex
+	defaultLang string
+	messages    map[string]map[string]string // lang -> i18nKey -> message
+	langs       []string
+	matcher     language.Matcher
+}
+
+// defaultLang is empty by default so responses keep AppError.Msg
+// unless the client sent a matching Accept-Language (see SetDefaultLanguage)
+var catalogue = &messageCatalogue{
+	messages: map[string]map[string]string{},
+}
+
+func init() {
+	RegisterMessages(LangKorean, map[string]string{
+		string(ErrBadParameter):           "잘못된 요청 파라미터입니다.",
+		string(ErrBadRequest):             "잘못된 요청입니다.",
+		string(ErrBadToken):               "유효하지 않은 토큰입니다.",
+		string(ErrPartner):                "외부 서비스 오류입니다.",
+		string(ErrForbidden):              "접근 권한이 없습니다.",
+		string(ErrNotFound):               "요청한 리소스를 찾을 수 없습니다.",
+		string(ErrTooManyRequest):         "요청이 너무 많습니다. 잠시 후 다시 시도해 주세요.",
+		string(ErrInternalServer):         "서버 내부 오류입니다.",
+		string(ErrInternalDB):             "데이터베이스 오류입니다.",
+		string(ErrCodeNotFound):           "인증 코드를 찾을 수 없습니다.",
+		string(ErrUserNotFound):           "사용자를 찾을 수 없습니다.",
+		string(ErrProfileNotFount):        "프로필을 찾을 수 없습니다.",
+		string(ErrUserAlreadyExisted):     "이미 가입된 사용자입니다.",
+		string(ErrPasswordNotMatch):       "비밀번호가 일치하지 않습니다.",
+		string(ErrInvalidEmailOrPassword): "이메일 또는 비밀번호가 올바르지 않습니다.",
+		string(ErrInvalidAccessToken):     "유효하지 않은 액세스 토큰입니다.",
+		string(ErrInvalidAuthCode):        "인증 코드가 올바르지 않습니다.",
+		string(ErrInsufficientRole):       "해당 기능을 사용할 권한이 없습니다.",
+		string(ErrInsufficientScope):      "토큰에 필요한 권한 범위가 없습니다.",
+		string(ErrNotResourceOwner):       "본인의 리소스만 접근할 수 있습니다.",
+		string(ErrRefreshTokenReused):     "이미 사용된 리프레시 토큰입니다. 다시 로그인해 주세요.",
+		string(ErrTokenRevoked):           "만료 처리된 토큰입니다. 다시 로그인해 주세요.",
+		string(ErrTokenExpired):           "토큰이 만료되었습니다.",
+		string(ErrWeakPassword):           "비밀번호가 보안 정책을 만족하지 않습니다.",
+		string(ErrIdentityAlreadyLinked):  "이미 다른 계정에 연결된 소셜 계정입니다.",
+		string(ErrLastIdentity):           "마지막 로그인 수단은 연결 해제할 수 없습니다.",
+	})
+	RegisterMessages(LangEnglish, map[string]string{
+		string(ErrBadParameter):           "Invalid request parameter.",
+		string(ErrBadRequest):             "Bad request.",
+		string(ErrBadToken):               "Invalid token.",
+		string(ErrPartner):                "External service error.",
+		string(ErrForbidden):              "Forbidden.",
+		string(ErrNotFound):               "The requested resource was not found.",
+		string(ErrTooManyRequest):         "Too many requests. Please try again later.",
+		string(ErrInternalServer):         "Internal server error.",
+		string(ErrInternalDB):             "Database error.",
+		string(ErrCodeNotFound):           "Auth code not found.",
+		string(ErrUserNotFound):           "User not found.",
+		string(ErrProfileNotFount):        "Profile not found.",
+		string(ErrUserAlreadyExisted):     "User already exists.",
+		string(ErrPasswordNotMatch):       "Password does not match.",
+		string(ErrInvalidEmailOrPassword): "Invalid email or password.",
+		string(ErrInvalidAccessToken):     "Invalid access token.",
+		string(ErrInvalidAuthCode):        "Invalid auth code.",
+		string(ErrInsufficientRole):       "You do not have the required role.",
+		string(ErrInsufficientScope):      "The token does not have the required scope.",
+		string(ErrNotResourceOwner):       "You can only access your own resources.",
+		string(ErrRefreshTokenReused):     "The refresh token was already used. Please log in again.",
+		string(ErrTokenRevoked):           "The token has been revoked. Please log in again.",
+		string(ErrTokenExpired):           "The token has expired.",
+		string(ErrWeakPassword):           "The password does not meet the password policy.",
+		string(ErrIdentityAlreadyLinked):  "This social account is already linked to another account.",
+		string(ErrLastIdentity):           "You cannot unlink your last login method.",
+	})
+}
+
+// RegisterMessages adds messages for a language keyed by ErrorSpec.I18nKey.
+// Messages may contain {key} placeholders filled from AppError.Details.
+func RegisterMessages(lang string, messages map[string]string) {
+	lang = language.Make(lang).String()
+
+	catalogue.mu.Lock()
+	defer catalogue.mu.Unlock()
+	if _, ok := catalogue.messages[lang]; !ok {
+		catalogue.messages[lang] = map[string]string{}
+		catalogue.langs = append(catalogue.langs, lang)
+		supported := make([]language.Tag, len(catalogue.langs))
+		for i, l := range catalogue.langs {
+			supported[i] = language.Make(l)
+		}
+		catalogue.matcher = language.NewMatcher(supported)
+	}
+	for key, msg := range messages {
+		catalogue.messages[lang][key] = msg
+	}
+}
+
+// SetDefaultLanguage sets the language used when Accept-Language is missing or does not match.
+// By default no language is used and AppError.Msg is kept.
+func SetDefaultLanguage(lang string) {
+	catalogue.mu.Lock()
+	defer catalogue.mu.Unlock()
+	if lang == "" {
+		catalogue.defaultLang = ""
+		return
+	}
+	catalogue.defaultLang = language.Make(lang).String()
+}
+
+// NegotiateLanguage picks the best registered language for an Accept-Language header.
+// It returns the default language (empty unless set) when nothing matches.
+func NegotiateLanguage(acceptLanguage string) string {
+	catalogue.mu.RLock()
+	defer catalogue.mu.RUnlock()
+
+	if acceptLanguage == "" || len(catalogue.langs) == 0 {
+		return catalogue.defaultLang
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return catalogue.defaultLang
+	}
+	_, index, confidence := catalogue.matcher.Match(tags...)
+	if confidence == language.No {
+		return catalogue.defaultLang
+	}
+	return catalogue.langs[index]
+}
+
+// LocalizeMessage returns the message for an error type in the given language.
+// It falls back to the default language and reports false when lang is empty
+// or no message is registered.
+func LocalizeMessage(lang string, errType string, details map[string]interface{}) (string, bool) {
+	if lang == "" {
+		return "", false
+	}
+	key := errType
+	if spec, ok := Lookup(ErrType(errType)); ok {
+		key = spec.I18nKey
+	}
+
+	catalogue.mu.RLock()
+	msg, ok := catalogue.messages[lang][key]
+	if !ok && catalogue.defaultLang != "" {
+		msg, ok = catalogue.messages[catalogue.defaultLang][key]
+	}
+	catalogue.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return fillPlaceholders(msg, details), true
+}
+
+// fillPlaceholders replaces {key} with the matching detail value
+func fillPlaceholders(msg string, details map[string]interface{}) string {
+	if len(details) == 0 || !strings.Contains(msg, "{") {
+		return msg
+	}
+	pairs := make([]string, 0, len(details)*2)
+	for key, value := range details {
+		pairs = append(pairs, "{"+key+"}", fmt.Sprintf("%v", value))
+	}
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
@@ -0,0 +1,121 @@
+package error
+
+/*
+	서비스별 에러 코드 등록 및 카탈로그
+*/
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorSpec describes a registered error type
+type ErrorSpec struct {
+	Type     ErrType `json:"errType"`
+	HttpCode int     `json:"httpCode"`
+	Msg      string  `json:"msg,omitempty"`
+	I18nKey  string  `json:"i18nKey,omitempty"`
+}
+
+// Registry holds the error types known to a service
+type Registry struct {
+	mu    sync.RWMutex
+	specs map[ErrType]ErrorSpec
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty error registry
+func NewRegistry() *Registry {
+	return &Registry{specs: map[ErrType]ErrorSpec{}}
+}
+
+// Register adds error specs to the registry.
+// Nothing is registered if any spec is invalid or already registered.
+func (r *Registry) Register(specs ...ErrorSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := map[ErrType]bool{}
+	for _, spec := range specs {
+		if spec.Type == "" {
+			return fmt.Errorf("error type is empty")
+		}
+		if spec.HttpCode < 100 || spec.HttpCode > 599 {
+			return fmt.Errorf("invalid http code %d for error type %s", spec.HttpCode, spec.Type)
+		}
+		if _, ok := r.specs[spec.Type]; ok || seen[spec.Type] {
+			return fmt.Errorf("error type %s is already registered", spec.Type)
+		}
+		seen[spec.Type] = true
+	}
+	for _, spec := range specs {
+		if spec.I18nKey == "" {
+			spec.I18nKey = string(spec.Type)
+		}
+		r.specs[spec.Type] = spec
+	}
+	return nil
+}
+
+// Lookup returns the spec registered for an error type
+func (r *Registry) Lookup(t ErrType) (ErrorSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, ok := r.specs[t]
+	return spec, ok
+}
+
+// Specs returns all registered specs sorted by error type
+func (r *Registry) Specs() []ErrorSpec {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	specs := make([]ErrorSpec, 0, len(r.specs))
+	for _, spec := range r.specs {
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Type < specs[j].Type })
+	return specs
+}
+
+// CatalogueJSON dumps all registered specs as JSON
+func (r *Registry) CatalogueJSON() ([]byte, error) {
+	return json.MarshalIndent(r.Specs(), "", "  ")
+}
+
+// Register adds error specs to the default registry
+func Register(specs ...ErrorSpec) error {
+	return defaultRegistry.Register(specs...)
+}
+
+// MustRegister is like Register but panics on conflicts. Call it during startup.
+func MustRegister(specs ...ErrorSpec) {
+	if err := Register(specs...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the spec registered in the default registry
+func Lookup(t ErrType) (ErrorSpec, bool) {
+	return defaultRegistry.Lookup(t)
+}
+
+// Catalogue returns all specs registered in the default registry
+func Catalogue() []ErrorSpec {
+	return defaultRegistry.Specs()
+}
+
+// CatalogueJSON dumps the default registry as JSON
+func CatalogueJSON() ([]byte, error) {
+	return defaultRegistry.CatalogueJSON()
+}
+
+// CatalogueHandler serves the default registry for client teams
+func CatalogueHandler(c echo.Context) error {
+	return c.JSON(http.StatusOK, Catalogue())
+}
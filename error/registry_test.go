@@ -0,0 +1,25 @@
+package error
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestRegisterServiceSpecs(t *testing.T) {
+	if _, ok := Lookup(ErrFoodNotFound); ok {
+		t.Fatalf("%s is registered before the service registers it", ErrFoodNotFound)
+	}
+	if got := HttpCodeOf(string(ErrFoodNotFound)); got != http.StatusInternalServerError {
+		t.Errorf("HttpCodeOf() before registration = %d, want %d", got, http.StatusInternalServerError)
+	}
+
+	if err := Register(FoodErrorSpecs...); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got := HttpCodeOf(string(ErrFoodNotFound)); got != http.StatusBadRequest {
+		t.Errorf("HttpCodeOf() = %d, want %d", got, http.StatusBadRequest)
+	}
+	if err := Register(FoodErrorSpecs...); err == nil {
+		t.Error("Register() of a duplicate spec error = nil, want error")
+	}
+}
@@ -4,6 +4,15 @@ package jwt
 	JWT 초기화 및 설정
 */
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/JokerTrickster/common/env"
+	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
 
@@ -19,19 +28,269 @@ const (
 	RefreshTokenExpiredTime = 24 * 7 // hour
 )
 
-// InitJWT initializes the JWT secrets and configuration
+// minSecretLength is the minimum HMAC key length accepted outside local mode
+const minSecretLength = 32
+
+// weakSecrets are rejected outside local mode regardless of length
+var weakSecrets = []string{"secret", "password", "changeme", "jwtsecret", "default"}
+
+// Config holds the JWT settings applied by InitJWTWithOptions
+type Config struct {
+	AccessSecret  []byte
+	RefreshSecret []byte
+	AccessTTL     time.Duration
+	RefreshTTL    time.Duration
+	Issuer        string
+	Audience      string
+	Leeway        time.Duration // allowed clock skew for exp/nbf/iat
+	IsLocal       bool
+	SigningKey    *SigningKey // access token key; defaults to HS256 with AccessSecret
+	KeyRing       *KeyRing    // takes precedence over SigningKey for rotation
+}
+
+// config is the active JWT configuration
+var config = Config{
+	AccessTTL:  AccessTokenExpiredTime * time.Hour,
+	RefreshTTL: RefreshTokenExpiredTime * time.Hour,
+}
+
+// ParamGetter fetches a secret by path (aws.SSMService implements it)
+type ParamGetter interface {
+	AwsSsmGetParam(ctx context.Context, path string) (string, error)
+}
+
+// Option configures InitJWTWithOptions
+type Option func(ctx context.Context, cfg *Config) error
+
+// WithSecrets sets the access and refresh signing keys directly
+func WithSecrets(accessSecret, refreshSecret []byte) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		cfg.AccessSecret = accessSecret
+		cfg.RefreshSecret = refreshSecret
+		return nil
+	}
+}
+
+// WithSecretsFromEnv reads the signing keys from environment variables
+func WithSecretsFromEnv(accessEnv, refreshEnv string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		access, ok := os.LookupEnv(accessEnv)
+		if !ok {
+			return fmt.Errorf("failed to retrieve environment variable: %s", accessEnv)
+		}
+		refresh, ok := os.LookupEnv(refreshEnv)
+		if !ok {
+			return fmt.Errorf("failed to retrieve environment variable: %s", refreshEnv)
+		}
+		cfg.AccessSecret = []byte(access)
+		cfg.RefreshSecret = []byte(refresh)
+		return nil
+	}
+}
+
+// WithSecretsFromSSM reads the signing keys from SSM parameters
+func WithSecretsFromSSM(ssm ParamGetter, accessPath, refreshPath string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		access, err := ssm.AwsSsmGetParam(ctx, accessPath)
+		if err != nil {
+			return fmt.Errorf("failed to fetch access token secret: %w", err)
+		}
+		refresh, err := ssm.AwsSsmGetParam(ctx, refreshPath)
+		if err != nil {
+			return fmt.Errorf("failed to fetch refresh token secret: %w", err)
+		}
+		cfg.AccessSecret = []byte(access)
+		cfg.RefreshSecret = []byte(refresh)
+		return nil
+	}
+}
+
+// WithSecretsFromFile reads the signing keys from files (ex. mounted secrets)
+func WithSecretsFromFile(accessPath, refreshPath string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		access, err := os.ReadFile(accessPath)
+		if err != nil {
+			return fmt.Errorf("failed to read access token secret: %w", err)
+		}
+		refresh, err := os.ReadFile(refreshPath)
+		if err != nil {
+			return fmt.Errorf("failed to read refresh token secret: %w", err)
+		}
+		cfg.AccessSecret = bytes.TrimSpace(access)
+		cfg.RefreshSecret = bytes.TrimSpace(refresh)
+		return nil
+	}
+}
+
+// WithSigningKey signs access tokens with the given key (ex. RS256/ES256/EdDSA)
+func WithSigningKey(key *SigningKey) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		cfg.SigningKey = key
+		return nil
+	}
+}
+
+// WithSigningKeyFromFile reads a PEM private key used to sign access tokens
+func WithSigningKeyFromFile(kid, path string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		pemBytes, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read signing key: %w", err)
+		}
+		key, err := ParsePrivateKeyPEM(kid, pemBytes)
+		if err != nil {
+			return err
+		}
+		cfg.SigningKey = key
+		return nil
+	}
+}
+
+// WithKeyRing signs access tokens with the ring's active key and verifies by kid
+func WithKeyRing(ring *KeyRing) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		if ring == nil || ring.Active() == nil {
+			return fmt.Errorf("key ring has no active key")
+		}
+		cfg.KeyRing = ring
+		cfg.SigningKey = ring.Active()
+		return nil
+	}
+}
+
+// WithTTL sets the access and refresh token lifetimes
+func WithTTL(accessTTL, refreshTTL time.Duration) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		if accessTTL <= 0 || refreshTTL <= 0 {
+			return fmt.Errorf("token TTL must be positive")
+		}
+		cfg.AccessTTL = accessTTL
+		cfg.RefreshTTL = refreshTTL
+		return nil
+	}
+}
+
+// WithIssuer sets the iss claim
+func WithIssuer(issuer string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		cfg.Issuer = issuer
+		return nil
+	}
+}
+
+// WithAudience sets the aud claim
+func WithAudience(audience string) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		cfg.Audience = audience
+		return nil
+	}
+}
+
+// WithLeeway sets the allowed clock skew when validating exp/nbf/iat
+func WithLeeway(leeway time.Duration) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		if leeway < 0 {
+			return fmt.Errorf("leeway must not be negative")
+		}
+		cfg.Leeway = leeway
+		return nil
+	}
+}
+
+// WithLocal overrides env.Env.IsLocal for key strength checks
+func WithLocal(isLocal bool) Option {
+	return func(ctx context.Context, cfg *Config) error {
+		cfg.IsLocal = isLocal
+		return nil
+	}
+}
+
+// InitJWT initializes the JWT secrets from JWT_ACCESS_SECRET / JWT_REFRESH_SECRET.
+// In local mode the legacy development secret is used when they are not set.
 func InitJWT() error {
-	// Initialize JWT Secret Keys
-	secret := "secret" // Replace this with a secure secret key
-	AccessTokenSecretKey = []byte(secret)
-	RefreshTokenSecretKey = []byte(secret)
+	opts := []Option{}
+	if _, ok := os.LookupEnv("JWT_ACCESS_SECRET"); ok || !env.Env.IsLocal {
+		opts = append(opts, WithSecretsFromEnv("JWT_ACCESS_SECRET", "JWT_REFRESH_SECRET"))
+	}
+	return InitJWTWithOptions(context.Background(), opts...)
+}
+
+// InitJWTWithOptions initializes the JWT secrets and configuration
+func InitJWTWithOptions(ctx context.Context, opts ...Option) error {
+	cfg := Config{
+		AccessTTL:  AccessTokenExpiredTime * time.Hour,
+		RefreshTTL: RefreshTokenExpiredTime * time.Hour,
+		IsLocal:    env.Env.IsLocal,
+	}
+	for _, opt := range opts {
+		if err := opt(ctx, &cfg); err != nil {
+			return err
+		}
+	}
+
+	if cfg.IsLocal && len(cfg.AccessSecret) == 0 && len(cfg.RefreshSecret) == 0 && cfg.SigningKey == nil {
+		// 로컬 개발용 기본 키
+		cfg.AccessSecret = []byte("secret")
+		cfg.RefreshSecret = []byte("secret")
+	}
+	if err := validateSecrets(cfg); err != nil {
+		return err
+	}
+
+	if cfg.SigningKey == nil {
+		cfg.SigningKey = NewHMACKey("", cfg.AccessSecret)
+	}
+	if cfg.KeyRing == nil {
+		cfg.KeyRing = NewKeyRing(cfg.SigningKey, cfg.AccessTTL)
+	}
+
+	config = cfg
+	AccessTokenSecretKey = cfg.AccessSecret
+	RefreshTokenSecretKey = cfg.RefreshSecret
 
 	// Configure JWT Middleware
 	JwtConfig = middleware.JWTConfig{
-		SigningKey:  AccessTokenSecretKey,   // Signing key for token verification
-		TokenLookup: "header:Authorization", // Where to look for the token
-		AuthScheme:  "Bearer",               // Authorization header prefix
+		ParseTokenFunc: parseTokenFunc,         // Same key lookup and claim checks as Verify
+		TokenLookup:    "header:Authorization", // Where to look for the token
+		AuthScheme:     "Bearer",               // Authorization header prefix
 	}
 
 	return nil
 }
+
+// parseTokenFunc verifies tokens for echo's JWT middleware.
+// The stored value is a *jwt.Token whose Claims are *Claims.
+func parseTokenFunc(auth string, c echo.Context) (interface{}, error) {
+	return verifyToken(c.Request().Context(), auth)
+}
+
+// validateSecrets rejects missing, weak or identical keys outside local mode.
+// The access secret is optional when access tokens use an asymmetric signing key.
+func validateSecrets(cfg Config) error {
+	secrets := map[string][]byte{"refresh": cfg.RefreshSecret}
+	if cfg.SigningKey == nil || !cfg.SigningKey.IsAsymmetric() {
+		secrets["access"] = cfg.AccessSecret
+	}
+	for name, secret := range secrets {
+		if len(secret) == 0 {
+			return fmt.Errorf("%s token secret is required", name)
+		}
+	}
+	if cfg.IsLocal {
+		return nil
+	}
+	for name, secret := range secrets {
+		if len(secret) < minSecretLength {
+			return fmt.Errorf("%s token secret must be at least %d bytes", name, minSecretLength)
+		}
+		for _, weak := range weakSecrets {
+			if strings.Contains(strings.ToLower(string(secret)), weak) {
+				return fmt.Errorf("%s token secret is too weak", name)
+			}
+		}
+	}
+	if bytes.Equal(cfg.AccessSecret, cfg.RefreshSecret) {
+		return fmt.Errorf("access and refresh token secrets must be different")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// serveWithJwtConfig runs a request with the bearer token through echo's JWT middleware
+func serveWithJwtConfig(t *testing.T, token string) (*Claims, error) {
+	t.Helper()
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	var claims *Claims
+	handler := middleware.JWTWithConfig(JwtConfig)(func(c echo.Context) error {
+		claims = c.Get("user").(*jwt.Token).Claims.(*Claims)
+		return c.NoContent(http.StatusOK)
+	})
+	return claims, handler(c)
+}
+
+func TestJwtConfig(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		alg     string // empty uses the default HS256 secret
+		token   string // overrides the generated token
+		wantErr bool
+	}{
+		{name: "HS256 secret"},
+		{name: "ES256 key without access secret", alg: "ES256"},
+		{name: "EdDSA key without access secret", alg: "EdDSA"},
+		{name: "malformed token", token: "not-a-token", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithLocal(true)}
+			if tt.alg != "" {
+				key, err := GenerateSigningKey(tt.alg)
+				if err != nil {
+					t.Fatalf("GenerateSigningKey() error = %v", err)
+				}
+				opts = append(opts, WithSecrets(nil, []byte("refresh")), WithSigningKey(key))
+			}
+			if err := InitJWTWithOptions(ctx, opts...); err != nil {
+				t.Fatalf("InitJWTWithOptions() error = %v", err)
+			}
+
+			token := tt.token
+			if token == "" {
+				var err error
+				if token, _, err = GenerateAccessToken("user@example.com", time.Now(), 1); err != nil {
+					t.Fatalf("GenerateAccessToken() error = %v", err)
+				}
+			}
+			claims, err := serveWithJwtConfig(t, token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JWT middleware error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && claims.UserID != 1 {
+				t.Errorf("claims.UserID = %d, want 1", claims.UserID)
+			}
+		})
+	}
+}
+
+func TestJwtConfigKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	first, err := GenerateSigningKey("ES256")
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	ring := NewKeyRing(first, time.Hour)
+	if err := InitJWTWithOptions(ctx, WithLocal(true), WithSecrets(nil, []byte("refresh")), WithKeyRing(ring)); err != nil {
+		t.Fatalf("InitJWTWithOptions() error = %v", err)
+	}
+	before, _, err := GenerateAccessToken("user@example.com", time.Now(), 1)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	next, err := GenerateSigningKey("EdDSA")
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	if err := ring.Rotate(next); err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	after, _, err := GenerateAccessToken("user@example.com", time.Now(), 1)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	for name, token := range map[string]string{"signed before rotation": before, "signed with the new key": after} {
+		if _, err := serveWithJwtConfig(t, token); err != nil {
+			t.Errorf("%s: JWT middleware error = %v", name, err)
+		}
+	}
+
+	ring.RemoveKey(first.KID)
+	if _, err := serveWithJwtConfig(t, before); err == nil {
+		t.Error("token of a removed key: JWT middleware error = nil, want error")
+	}
+}
@@ -11,33 +11,235 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/hex"
+	"fmt"
 	"io"
+	"reflect"
+	"strings"
 
 	_error "github.com/JokerTrickster/common/error"
 )
 
+// envelopeVersion prefixes ciphertexts produced by AESCipher
+const envelopeVersion = "v1"
+
 // AES encryption logic
 
 func AesEncrypt(ctx context.Context, byteToEncrypt []byte, keyString string) (string, error) {
+	return AesEncryptWithAAD(ctx, byteToEncrypt, keyString, nil)
+}
+
+// AesDecrypt decrypts a ciphertext produced by AesEncrypt
+func AesDecrypt(ctx context.Context, cipherText string, keyString string) ([]byte, error) {
+	return AesDecryptWithAAD(ctx, cipherText, keyString, nil)
+}
+
+// AesEncryptWithAAD encrypts with associated data that must be given again to decrypt
+func AesEncryptWithAAD(ctx context.Context, byteToEncrypt []byte, keyString string, aad []byte) (string, error) {
+	key, err := hex.DecodeString(keyString)
+	if err != nil {
+		return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode AES key", string(_error.ErrFromInternal))
+	}
+	nonce, sealed, err := seal(ctx, key, byteToEncrypt, aad)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(append(nonce, sealed...)), nil
+}
+
+// AesDecryptWithAAD decrypts a ciphertext produced by AesEncryptWithAAD
+func AesDecryptWithAAD(ctx context.Context, cipherText string, keyString string, aad []byte) ([]byte, error) {
 	key, err := hex.DecodeString(keyString)
 	if err != nil {
-		return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode AES key", string(_error.ErrFromClient))
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode AES key", string(_error.ErrFromInternal))
+	}
+	data, err := base64.StdEncoding.DecodeString(cipherText)
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode ciphertext", string(_error.ErrFromInternal))
+	}
+	aesGCM, err := newGCM(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aesGCM.NonceSize() {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "ciphertext too short", string(_error.ErrFromInternal))
+	}
+	return open(ctx, aesGCM, data[:aesGCM.NonceSize()], data[aesGCM.NonceSize():], aad)
+}
+
+// AESCipher encrypts with the active key and decrypts with any known key.
+// Ciphertexts use the envelope "v1:<keyID>:<base64 nonce>:<base64 ciphertext>" so keys can be rotated.
+type AESCipher struct {
+	activeKID string
+	keys      map[string][]byte
+}
+
+// NewAESCipher creates a cipher from hex encoded 16/24/32 byte keys keyed by key ID
+func NewAESCipher(activeKID string, hexKeys map[string]string) (*AESCipher, error) {
+	keys := map[string][]byte{}
+	for kid, hexKey := range hexKeys {
+		if kid == "" || strings.Contains(kid, ":") {
+			return nil, fmt.Errorf("invalid AES key ID: %q", kid)
+		}
+		key, err := hex.DecodeString(hexKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode AES key %s: %w", kid, err)
+		}
+		if _, err := aes.NewCipher(key); err != nil {
+			return nil, fmt.Errorf("invalid AES key %s: %w", kid, err)
+		}
+		keys[kid] = key
+	}
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("active AES key %s not found", activeKID)
+	}
+	return &AESCipher{activeKID: activeKID, keys: keys}, nil
+}
+
+// Encrypt encrypts plaintext with the active key
+func (c *AESCipher) Encrypt(ctx context.Context, plaintext []byte, aad []byte) (string, error) {
+	nonce, sealed, err := seal(ctx, c.keys[c.activeKID], plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		envelopeVersion,
+		c.activeKID,
+		base64.RawStdEncoding.EncodeToString(nonce),
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+// Decrypt decrypts an envelope with the key named in it
+func (c *AESCipher) Decrypt(ctx context.Context, envelope string, aad []byte) ([]byte, error) {
+	parts := strings.Split(envelope, ":")
+	if len(parts) != 4 || parts[0] != envelopeVersion {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "invalid ciphertext envelope", string(_error.ErrFromInternal))
+	}
+	key, ok := c.keys[parts[1]]
+	if !ok {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), fmt.Sprintf("unknown AES key ID: %s", parts[1]), string(_error.ErrFromInternal))
+	}
+	nonce, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode nonce", string(_error.ErrFromInternal))
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode ciphertext", string(_error.ErrFromInternal))
+	}
+	aesGCM, err := newGCM(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aesGCM.NonceSize() {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "invalid nonce size", string(_error.ErrFromInternal))
+	}
+	return open(ctx, aesGCM, nonce, sealed, aad)
+}
+
+// IsEncrypted reports whether the value is an AESCipher or EnvelopeCipher envelope
+func IsEncrypted(value string) bool {
+	return (strings.HasPrefix(value, envelopeVersion+":") || strings.HasPrefix(value, envelopeKeyVersion+":")) &&
+		strings.Count(value, ":") == 3
+}
+
+// EncryptFields encrypts the string fields of a struct pointer tagged `encrypt:"<label>"`.
+// The label is used as associated data, so a value cannot be moved to another column.
+// Every non-empty value is encrypted, even one that looks like an envelope,
+// so call it once per write on plaintext values.
+//
+//	type Users struct {
+//		Email string `encrypt:"users.email"`
+//	}
+func EncryptFields(ctx context.Context, c FieldCipher, v interface{}) error {
+	return eachEncryptedField(ctx, v, func(field reflect.Value, label string) error {
+		value := field.String()
+		if value == "" {
+			return nil
+		}
+		encrypted, err := c.Encrypt(ctx, []byte(value), []byte(label))
+		if err != nil {
+			return err
+		}
+		field.SetString(encrypted)
+		return nil
+	})
+}
+
+// DecryptFields decrypts the fields encrypted by EncryptFields.
+// Plaintext values (ex. rows written before encryption was enabled) are left as they are.
+func DecryptFields(ctx context.Context, c FieldCipher, v interface{}) error {
+	return eachEncryptedField(ctx, v, func(field reflect.Value, label string) error {
+		value := field.String()
+		if !IsEncrypted(value) {
+			return nil
+		}
+		decrypted, err := c.Decrypt(ctx, value, []byte(label))
+		if err != nil {
+			return err
+		}
+		field.SetString(string(decrypted))
+		return nil
+	})
+}
+
+// eachEncryptedField calls fn for every settable string field with an encrypt tag
+func eachEncryptedField(ctx context.Context, v interface{}, fn func(field reflect.Value, label string) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "encrypted fields require a struct pointer", string(_error.ErrFromInternal))
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		label, ok := rt.Field(i).Tag.Lookup("encrypt")
+		if !ok || label == "" || label == "-" {
+			continue
+		}
+		field := rv.Field(i)
+		if field.Kind() != reflect.String || !field.CanSet() {
+			return _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), fmt.Sprintf("field %s must be a settable string", rt.Field(i).Name), string(_error.ErrFromInternal))
+		}
+		if err := fn(field, label); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+// newGCM creates an AES-GCM AEAD for the key
+func newGCM(ctx context.Context, key []byte) (cipher.AEAD, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
-		return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create cipher", string(_error.ErrFromInternal))
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create cipher", string(_error.ErrFromInternal))
 	}
 
 	aesGCM, err := cipher.NewGCM(block)
 	if err != nil {
-		return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create GCM", string(_error.ErrFromInternal))
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create GCM", string(_error.ErrFromInternal))
+	}
+	return aesGCM, nil
+}
+
+// seal encrypts plaintext with a random nonce
+func seal(ctx context.Context, key []byte, plaintext []byte, aad []byte) ([]byte, []byte, error) {
+	aesGCM, err := newGCM(ctx, key)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	nonce := make([]byte, aesGCM.NonceSize())
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
-		return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create nonce", string(_error.ErrFromInternal))
+		return nil, nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to create nonce", string(_error.ErrFromInternal))
 	}
+	return nonce, aesGCM.Seal(nil, nonce, plaintext, aad), nil
+}
 
-	return base64.StdEncoding.EncodeToString(aesGCM.Seal(nonce, nonce, byteToEncrypt, nil)), nil
+// open decrypts and authenticates the ciphertext
+func open(ctx context.Context, aesGCM cipher.AEAD, nonce, sealed, aad []byte) ([]byte, error) {
+	plaintext, err := aesGCM.Open(nil, nonce, sealed, aad)
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decrypt ciphertext", string(_error.ErrFromInternal))
+	}
+	return plaintext, nil
 }
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+type encryptedRecord struct {
+	Email string `encrypt:"users.email"`
+	Phone string `encrypt:"users.phone"`
+	Name  string
+}
+
+func newTestAESCipher(t *testing.T) *AESCipher {
+	t.Helper()
+	c, err := NewAESCipher("k2", map[string]string{
+		"k1": strings.Repeat("11", 32),
+		"k2": strings.Repeat("22", 32),
+	})
+	if err != nil {
+		t.Fatalf("NewAESCipher() error = %v", err)
+	}
+	return c
+}
+
+func TestEncryptFieldsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	c := newTestAESCipher(t)
+	tests := []struct {
+		name   string
+		record encryptedRecord
+	}{
+		{name: "plain values", record: encryptedRecord{Email: "user@example.com", Phone: "010-1234-5678", Name: "kim"}},
+		{name: "empty value", record: encryptedRecord{Email: "user@example.com"}},
+		{name: "value that looks like an envelope", record: encryptedRecord{Email: "v1:a:b:c", Phone: "env1:a:b:c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			if err := EncryptFields(ctx, c, &record); err != nil {
+				t.Fatalf("EncryptFields() error = %v", err)
+			}
+			if record.Name != tt.record.Name {
+				t.Errorf("untagged field changed: %q", record.Name)
+			}
+			for _, pair := range [][2]string{{record.Email, tt.record.Email}, {record.Phone, tt.record.Phone}} {
+				if pair[1] == "" && pair[0] != "" {
+					t.Errorf("empty value was encrypted: %q", pair[0])
+				}
+				if pair[1] != "" && (pair[0] == pair[1] || !IsEncrypted(pair[0])) {
+					t.Errorf("value %q was not encrypted: %q", pair[1], pair[0])
+				}
+			}
+
+			if err := DecryptFields(ctx, c, &record); err != nil {
+				t.Fatalf("DecryptFields() error = %v", err)
+			}
+			if record != tt.record {
+				t.Errorf("round trip = %+v, want %+v", record, tt.record)
+			}
+		})
+	}
+}
+
+func TestDecryptFields(t *testing.T) {
+	ctx := context.Background()
+	c := newTestAESCipher(t)
+	oldKey, err := NewAESCipher("k1", map[string]string{"k1": strings.Repeat("11", 32)})
+	if err != nil {
+		t.Fatalf("NewAESCipher() error = %v", err)
+	}
+	oldEmail, err := oldKey.Encrypt(ctx, []byte("old@example.com"), []byte("users.email"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	moved, err := c.Encrypt(ctx, []byte("010-1234-5678"), []byte("users.phone"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		record  encryptedRecord
+		want    encryptedRecord
+		wantErr bool
+	}{
+		{name: "legacy plaintext", record: encryptedRecord{Email: "user@example.com"}, want: encryptedRecord{Email: "user@example.com"}},
+		{name: "rotated key", record: encryptedRecord{Email: oldEmail}, want: encryptedRecord{Email: "old@example.com"}},
+		{name: "value moved to another column", record: encryptedRecord{Email: moved}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			err := DecryptFields(ctx, c, &record)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, _error.ErrInternalServer) {
+				t.Errorf("DecryptFields() error = %v, want %s", err, _error.ErrInternalServer)
+			}
+			if !tt.wantErr && record != tt.want {
+				t.Errorf("DecryptFields() = %+v, want %+v", record, tt.want)
+			}
+		})
+	}
+}
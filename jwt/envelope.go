@@ -0,0 +1,297 @@
+package jwt
+
+/*
+	봉투 암호화 (Envelope Encryption)
+	- 레코드마다 데이터 키를 생성해 데이터를 암호화하고, 데이터 키는 마스터 키(KMS 등)로 암호화해 함께 저장합니다.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+// envelopeKeyVersion prefixes ciphertexts produced by EnvelopeCipher
+const envelopeKeyVersion = "env1"
+
+// dataKeyAAD binds wrapped data keys to their purpose
+var dataKeyAAD = []byte("data-key")
+
+// KeyProvider generates and unwraps data keys with a master key.
+// aws.KMSService, LocalKeyProvider and FakeKeyProvider implement it.
+type KeyProvider interface {
+	// GenerateDataKey returns a new 32 byte data key in plaintext and encrypted form
+	GenerateDataKey(ctx context.Context) (plaintext []byte, encrypted []byte, err error)
+	// DecryptDataKey returns the plaintext of an encrypted data key
+	DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error)
+}
+
+// FieldCipher encrypts values for EncryptFields / DecryptFields
+type FieldCipher interface {
+	Encrypt(ctx context.Context, plaintext []byte, aad []byte) (string, error)
+	Decrypt(ctx context.Context, ciphertext string, aad []byte) ([]byte, error)
+}
+
+// EnvelopeCipher encrypts values with data keys wrapped by a KeyProvider.
+// Ciphertexts use "env1:<base64 encrypted data key>:<base64 nonce>:<base64 ciphertext>".
+type EnvelopeCipher struct {
+	provider KeyProvider
+	legacy   *AESCipher
+}
+
+// NewEnvelopeCipher creates an envelope cipher
+func NewEnvelopeCipher(provider KeyProvider) *EnvelopeCipher {
+	return &EnvelopeCipher{provider: provider}
+}
+
+// WithLegacyCipher lets Decrypt read "v1:" values written by an AESCipher before migrating
+func (e *EnvelopeCipher) WithLegacyCipher(legacy *AESCipher) *EnvelopeCipher {
+	e.legacy = legacy
+	return e
+}
+
+// Encrypt encrypts plaintext with a new data key
+func (e *EnvelopeCipher) Encrypt(ctx context.Context, plaintext []byte, aad []byte) (string, error) {
+	dataKey, err := e.newDataKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer dataKey.wipe()
+	return dataKey.Encrypt(ctx, plaintext, aad)
+}
+
+// Decrypt decrypts an envelope produced by Encrypt or EncryptFields
+func (e *EnvelopeCipher) Decrypt(ctx context.Context, ciphertext string, aad []byte) ([]byte, error) {
+	reader := e.newReader()
+	defer reader.wipe()
+	return reader.Decrypt(ctx, ciphertext, aad)
+}
+
+// EncryptFields encrypts the tagged fields of a record with one data key per record
+func (e *EnvelopeCipher) EncryptFields(ctx context.Context, v interface{}) error {
+	dataKey, err := e.newDataKey(ctx)
+	if err != nil {
+		return err
+	}
+	defer dataKey.wipe()
+	return EncryptFields(ctx, dataKey, v)
+}
+
+// DecryptFields decrypts the tagged fields of a record, unwrapping each data key once
+func (e *EnvelopeCipher) DecryptFields(ctx context.Context, v interface{}) error {
+	reader := e.newReader()
+	defer reader.wipe()
+	return DecryptFields(ctx, reader, v)
+}
+
+// newReader creates a reader with an empty data key cache
+func (e *EnvelopeCipher) newReader() *envelopeReader {
+	return &envelopeReader{provider: e.provider, legacy: e.legacy, keys: map[string][]byte{}}
+}
+
+// newDataKey asks the provider for a new data key
+func (e *EnvelopeCipher) newDataKey(ctx context.Context) (*dataKeyCipher, error) {
+	plaintext, encrypted, err := e.provider.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to generate data key", string(_error.ErrFromAws))
+	}
+	return &dataKeyCipher{key: plaintext, encrypted: base64.RawStdEncoding.EncodeToString(encrypted)}, nil
+}
+
+// dataKeyCipher encrypts with a single plaintext data key
+type dataKeyCipher struct {
+	key       []byte
+	encrypted string
+}
+
+func (d *dataKeyCipher) Encrypt(ctx context.Context, plaintext []byte, aad []byte) (string, error) {
+	nonce, sealed, err := seal(ctx, d.key, plaintext, aad)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join([]string{
+		envelopeKeyVersion,
+		d.encrypted,
+		base64.RawStdEncoding.EncodeToString(nonce),
+		base64.RawStdEncoding.EncodeToString(sealed),
+	}, ":"), nil
+}
+
+func (d *dataKeyCipher) Decrypt(ctx context.Context, ciphertext string, aad []byte) ([]byte, error) {
+	return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "data key cipher is encrypt-only", string(_error.ErrFromInternal))
+}
+
+func (d *dataKeyCipher) wipe() {
+	for i := range d.key {
+		d.key[i] = 0
+	}
+}
+
+// envelopeReader decrypts envelopes and caches unwrapped data keys.
+// "v1:" values are passed to the legacy AESCipher when one is set.
+type envelopeReader struct {
+	provider KeyProvider
+	legacy   *AESCipher
+	keys     map[string][]byte
+}
+
+func (r *envelopeReader) Encrypt(ctx context.Context, plaintext []byte, aad []byte) (string, error) {
+	return "", _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "envelope reader is decrypt-only", string(_error.ErrFromInternal))
+}
+
+func (r *envelopeReader) Decrypt(ctx context.Context, ciphertext string, aad []byte) ([]byte, error) {
+	if strings.HasPrefix(ciphertext, envelopeVersion+":") {
+		if r.legacy == nil {
+			return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "legacy AES ciphertext requires WithLegacyCipher", string(_error.ErrFromInternal))
+		}
+		return r.legacy.Decrypt(ctx, ciphertext, aad)
+	}
+	parts := strings.Split(ciphertext, ":")
+	if len(parts) != 4 || parts[0] != envelopeKeyVersion {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "invalid envelope", string(_error.ErrFromInternal))
+	}
+	key, ok := r.keys[parts[1]]
+	if !ok {
+		encrypted, err := base64.RawStdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode data key", string(_error.ErrFromInternal))
+		}
+		key, err = r.provider.DecryptDataKey(ctx, encrypted)
+		if err != nil {
+			return nil, _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to decrypt data key", string(_error.ErrFromAws))
+		}
+		r.keys[parts[1]] = key
+	}
+	nonce, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode nonce", string(_error.ErrFromInternal))
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "failed to decode ciphertext", string(_error.ErrFromInternal))
+	}
+	aesGCM, err := newGCM(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aesGCM.NonceSize() {
+		return nil, _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "invalid nonce size", string(_error.ErrFromInternal))
+	}
+	return open(ctx, aesGCM, nonce, sealed, aad)
+}
+
+func (r *envelopeReader) wipe() {
+	for _, key := range r.keys {
+		for i := range key {
+			key[i] = 0
+		}
+	}
+}
+
+// LocalKeyProvider wraps data keys with a local master key (env or file).
+// Master keys can be rotated through the underlying AESCipher key IDs.
+type LocalKeyProvider struct {
+	master *AESCipher
+}
+
+// NewLocalKeyProvider creates a provider from hex encoded master keys keyed by key ID
+func NewLocalKeyProvider(activeKID string, hexKeys map[string]string) (*LocalKeyProvider, error) {
+	master, err := NewAESCipher(activeKID, hexKeys)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalKeyProvider{master: master}, nil
+}
+
+// NewLocalKeyProviderFromEnv reads a hex encoded master key from an environment variable
+func NewLocalKeyProviderFromEnv(kid, envName string) (*LocalKeyProvider, error) {
+	hexKey, ok := os.LookupEnv(envName)
+	if !ok {
+		return nil, fmt.Errorf("failed to retrieve environment variable: %s", envName)
+	}
+	return NewLocalKeyProvider(kid, map[string]string{kid: strings.TrimSpace(hexKey)})
+}
+
+// NewLocalKeyProviderFromFile reads a hex encoded master key from a file
+func NewLocalKeyProviderFromFile(kid, path string) (*LocalKeyProvider, error) {
+	hexKey, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key: %w", err)
+	}
+	return NewLocalKeyProvider(kid, map[string]string{kid: strings.TrimSpace(string(hexKey))})
+}
+
+// GenerateDataKey returns a random data key wrapped with the active master key
+func (p *LocalKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	encrypted, err := p.master.Encrypt(ctx, dataKey, dataKeyAAD)
+	if err != nil {
+		return nil, nil, err
+	}
+	return dataKey, []byte(encrypted), nil
+}
+
+// DecryptDataKey unwraps a data key with the master key named in it
+func (p *LocalKeyProvider) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	return p.master.Decrypt(ctx, string(encrypted), dataKeyAAD)
+}
+
+// FakeKeyProvider is an in-memory KeyProvider for tests.
+// Set Err before use, or call SetErr while calls are running, to make every call fail.
+// Generated and Decrypted count the calls and are safe for concurrent use.
+type FakeKeyProvider struct {
+	Err       error
+	Generated atomic.Int64
+	Decrypted atomic.Int64
+	local     *LocalKeyProvider
+	mu        sync.Mutex
+}
+
+// NewFakeKeyProvider creates a fake provider with a random master key
+func NewFakeKeyProvider() *FakeKeyProvider {
+	master := make([]byte, 32)
+	_, _ = io.ReadFull(rand.Reader, master)
+	local, _ := NewLocalKeyProvider("fake", map[string]string{"fake": fmt.Sprintf("%x", master)})
+	return &FakeKeyProvider{local: local}
+}
+
+// SetErr sets the error returned by every call
+func (p *FakeKeyProvider) SetErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Err = err
+}
+
+// failure returns Err under the lock
+func (p *FakeKeyProvider) failure() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.Err
+}
+
+func (p *FakeKeyProvider) GenerateDataKey(ctx context.Context) ([]byte, []byte, error) {
+	p.Generated.Add(1)
+	if err := p.failure(); err != nil {
+		return nil, nil, err
+	}
+	return p.local.GenerateDataKey(ctx)
+}
+
+func (p *FakeKeyProvider) DecryptDataKey(ctx context.Context, encrypted []byte) ([]byte, error) {
+	p.Decrypted.Add(1)
+	if err := p.failure(); err != nil {
+		return nil, err
+	}
+	return p.local.DecryptDataKey(ctx, encrypted)
+}
@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEnvelopeCipherFields(t *testing.T) {
+	ctx := context.Background()
+	legacy := newTestAESCipher(t)
+	legacyEmail, err := legacy.Encrypt(ctx, []byte("legacy@example.com"), []byte("users.email"))
+	if err != nil {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		legacy    *AESCipher
+		record    encryptedRecord
+		encrypt   bool
+		want      encryptedRecord
+		wantErr   bool
+		decrypted int64 // data keys unwrapped by DecryptFields
+	}{
+		{
+			name:      "round trip with one data key per record",
+			record:    encryptedRecord{Email: "user@example.com", Phone: "v1:a:b:c"},
+			encrypt:   true,
+			want:      encryptedRecord{Email: "user@example.com", Phone: "v1:a:b:c"},
+			decrypted: 1,
+		},
+		{
+			name:   "legacy AES value with legacy cipher",
+			legacy: legacy,
+			record: encryptedRecord{Email: legacyEmail},
+			want:   encryptedRecord{Email: "legacy@example.com"},
+		},
+		{
+			name:    "legacy AES value without legacy cipher",
+			record:  encryptedRecord{Email: legacyEmail},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewFakeKeyProvider()
+			c := NewEnvelopeCipher(provider).WithLegacyCipher(tt.legacy)
+			record := tt.record
+			if tt.encrypt {
+				if err := c.EncryptFields(ctx, &record); err != nil {
+					t.Fatalf("EncryptFields() error = %v", err)
+				}
+				if !strings.HasPrefix(record.Email, envelopeKeyVersion+":") || !strings.HasPrefix(record.Phone, envelopeKeyVersion+":") {
+					t.Fatalf("EncryptFields() = %+v, want env1 envelopes", record)
+				}
+			}
+
+			err := c.DecryptFields(ctx, &record)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if record != tt.want {
+				t.Errorf("DecryptFields() = %+v, want %+v", record, tt.want)
+			}
+			if got := provider.Decrypted.Load(); got != tt.decrypted {
+				t.Errorf("data keys unwrapped = %d, want %d", got, tt.decrypted)
+			}
+		})
+	}
+}
+
+func TestFakeKeyProviderConcurrent(t *testing.T) {
+	ctx := context.Background()
+	provider := NewFakeKeyProvider()
+	c := NewEnvelopeCipher(provider)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ciphertext, err := c.Encrypt(ctx, []byte("value"), nil)
+			if err != nil {
+				t.Errorf("Encrypt() error = %v", err)
+				return
+			}
+			if _, err := c.Decrypt(ctx, ciphertext, nil); err != nil {
+				t.Errorf("Decrypt() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := provider.Generated.Load(); got != 20 {
+		t.Errorf("Generated = %d, want 20", got)
+	}
+	if got := provider.Decrypted.Load(); got != 20 {
+		t.Errorf("Decrypted = %d, want 20", got)
+	}
+
+	// SetErr must not race with running calls
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = c.Encrypt(ctx, []byte("value"), nil)
+		}()
+	}
+	provider.SetErr(errors.New("kms unavailable"))
+	wg.Wait()
+	if _, err := c.Encrypt(ctx, []byte("value"), nil); err == nil {
+		t.Error("Encrypt() error = nil, want provider error")
+	}
+}
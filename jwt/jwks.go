@@ -0,0 +1,45 @@
+package jwt
+
+/*
+	공개키 JWKS 엔드포인트
+*/
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+// PublicJWKS returns the public keys of all keys that still verify access tokens
+func PublicJWKS() (jwk.Set, error) {
+	set := jwk.NewSet()
+	for _, key := range accessKeyRing().VerifyKeys() {
+		if !key.IsAsymmetric() {
+			continue
+		}
+		jwkKey, err := jwk.New(key.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JWK: %w", err)
+		}
+		if key.KID != "" {
+			_ = jwkKey.Set(jwk.KeyIDKey, key.KID)
+		}
+		_ = jwkKey.Set(jwk.AlgorithmKey, key.Method.Alg())
+		_ = jwkKey.Set(jwk.KeyUsageKey, string(jwk.ForSignature))
+		set.Add(jwkKey)
+	}
+	return set, nil
+}
+
+// JWKSHandler serves the public keys as a JWKS document.
+// ex) e.GET("/.well-known/jwks.json", jwt.JWKSHandler)
+func JWKSHandler(c echo.Context) error {
+	set, err := PublicJWKS()
+	if err != nil {
+		return err
+	}
+	c.Response().Header().Set("Cache-Control", "public, max-age=300")
+	return c.JSON(http.StatusOK, set)
+}
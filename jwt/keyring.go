@@ -0,0 +1,192 @@
+package jwt
+
+/*
+	서명 키 로테이션 (활성 키 1개 + 검증 전용 키 여러 개)
+*/
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// KeyGenerator creates the next signing key during scheduled rotation.
+// With several instances it should load a shared key (ex. from SSM) instead of generating one.
+type KeyGenerator func(ctx context.Context) (*SigningKey, error)
+
+// KeyRing holds one active signing key and verify-only keys selected by kid
+type KeyRing struct {
+	mu     sync.RWMutex
+	active *SigningKey
+	verify map[string]verifyKey
+	grace  time.Duration
+}
+
+type verifyKey struct {
+	key      *SigningKey
+	retireAt time.Time // zero means never retired
+}
+
+// NewKeyRing creates a key ring. Rotated-out keys keep verifying tokens for grace.
+func NewKeyRing(active *SigningKey, grace time.Duration) *KeyRing {
+	return &KeyRing{
+		active: active,
+		verify: map[string]verifyKey{},
+		grace:  grace,
+	}
+}
+
+// Active returns the key used to sign new tokens
+func (r *KeyRing) Active() *SigningKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.active
+}
+
+// AddVerifyKey adds a verify-only key; a zero retireAt keeps it until removed
+func (r *KeyRing) AddVerifyKey(key *SigningKey, retireAt time.Time) error {
+	if key.KID == "" {
+		return fmt.Errorf("verify key must have a key ID")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active != nil && r.active.KID == key.KID {
+		return fmt.Errorf("key ID %s is already active", key.KID)
+	}
+	r.verify[key.KID] = verifyKey{key: key, retireAt: retireAt}
+	return nil
+}
+
+// RemoveKey drops a verify-only key immediately
+func (r *KeyRing) RemoveKey(kid string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.verify, kid)
+}
+
+// Rotate makes next the active key and keeps the previous one for the grace period.
+// A previous key without kid (ex. the default HMAC key) keeps verifying tokens without kid.
+func (r *KeyRing) Rotate(next *SigningKey) error {
+	if next.KID == "" {
+		return fmt.Errorf("signing key must have a key ID")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.active != nil {
+		if r.active.KID == next.KID {
+			return fmt.Errorf("key ID %s is already active", next.KID)
+		}
+		r.verify[r.active.KID] = verifyKey{key: r.active, retireAt: time.Now().Add(r.grace)}
+	}
+	delete(r.verify, next.KID)
+	r.active = next
+	return nil
+}
+
+// Lookup returns the active or non-retired key for kid.
+// Tokens without kid are checked against the rotated-out kid-less key during its
+// grace period, otherwise against the active key.
+func (r *KeyRing) Lookup(kid string) (*SigningKey, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.active != nil && r.active.KID == kid {
+		return r.active, true
+	}
+	if entry, ok := r.verify[kid]; ok && !entry.isRetired(time.Now()) {
+		return entry.key, true
+	}
+	if kid == "" && r.active != nil {
+		return r.active, true
+	}
+	return nil, false
+}
+
+// VerifyKeys returns the active key and all non-retired verify keys
+func (r *KeyRing) VerifyKeys() []*SigningKey {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	keys := []*SigningKey{}
+	if r.active != nil {
+		keys = append(keys, r.active)
+	}
+	for kid, entry := range r.verify {
+		if entry.isRetired(now) {
+			delete(r.verify, kid)
+			continue
+		}
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// StartRotation rotates the active key every interval until ctx is done
+func (r *KeyRing) StartRotation(ctx context.Context, interval time.Duration, generate KeyGenerator) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				next, err := generate(ctx)
+				if err != nil {
+					log.Printf("Failed to generate signing key: %v", err)
+					continue
+				}
+				if err := r.Rotate(next); err != nil {
+					log.Printf("Failed to rotate signing key: %v", err)
+					continue
+				}
+				log.Printf("Rotated signing key to %s", next.KID)
+			}
+		}
+	}()
+}
+
+func (v verifyKey) isRetired(now time.Time) bool {
+	return !v.retireAt.IsZero() && now.After(v.retireAt)
+}
+
+// GenerateSigningKey creates a new random key with a UUID key ID.
+// Supported algorithms: HS256, RS256, ES256, EdDSA
+func GenerateSigningKey(alg string) (*SigningKey, error) {
+	kid := uuid.New().String()
+	switch alg {
+	case "HS256":
+		secret := make([]byte, 64)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		return NewHMACKey(kid, secret), nil
+	case "RS256":
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSAKey(kid, privateKey), nil
+	case "ES256":
+		privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewECDSAKey(kid, privateKey)
+	case "EdDSA":
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return NewEd25519Key(kid, privateKey), nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+}
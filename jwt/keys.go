@@ -0,0 +1,129 @@
+package jwt
+
+/*
+	토큰 서명 키 (HS256 / RS256 / ES256 / EdDSA)
+*/
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// SigningKey is a key used to sign and verify tokens
+type SigningKey struct {
+	KID        string
+	Method     jwt.SigningMethod
+	PrivateKey interface{} // []byte, *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+	PublicKey  interface{} // nil for HMAC keys
+}
+
+// NewHMACKey creates an HS256 key
+func NewHMACKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{
+		KID:        kid,
+		Method:     jwt.SigningMethodHS256,
+		PrivateKey: secret,
+	}
+}
+
+// NewRSAKey creates an RS256 key
+func NewRSAKey(kid string, privateKey *rsa.PrivateKey) *SigningKey {
+	return &SigningKey{
+		KID:        kid,
+		Method:     jwt.SigningMethodRS256,
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+	}
+}
+
+// NewECDSAKey creates an ES256/ES384/ES512 key depending on the curve
+func NewECDSAKey(kid string, privateKey *ecdsa.PrivateKey) (*SigningKey, error) {
+	var method jwt.SigningMethod
+	switch privateKey.Curve {
+	case elliptic.P256():
+		method = jwt.SigningMethodES256
+	case elliptic.P384():
+		method = jwt.SigningMethodES384
+	case elliptic.P521():
+		method = jwt.SigningMethodES512
+	default:
+		return nil, fmt.Errorf("unsupported ECDSA curve: %s", privateKey.Curve.Params().Name)
+	}
+	return &SigningKey{
+		KID:        kid,
+		Method:     method,
+		PrivateKey: privateKey,
+		PublicKey:  &privateKey.PublicKey,
+	}, nil
+}
+
+// NewEd25519Key creates an EdDSA key
+func NewEd25519Key(kid string, privateKey ed25519.PrivateKey) *SigningKey {
+	return &SigningKey{
+		KID:        kid,
+		Method:     SigningMethodEd25519,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.Public(),
+	}
+}
+
+// ParsePrivateKeyPEM parses a PKCS#1, PKCS#8 or SEC 1 PEM private key
+func ParsePrivateKeyPEM(kid string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode PEM block")
+	}
+
+	var privateKey interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	switch key := privateKey.(type) {
+	case *rsa.PrivateKey:
+		return NewRSAKey(kid, key), nil
+	case *ecdsa.PrivateKey:
+		return NewECDSAKey(kid, key)
+	case ed25519.PrivateKey:
+		return NewEd25519Key(kid, key), nil
+	}
+	return nil, fmt.Errorf("unsupported private key type: %T", privateKey)
+}
+
+// IsAsymmetric reports whether the key has a public half that can be published
+func (k *SigningKey) IsAsymmetric() bool {
+	return k.PublicKey != nil
+}
+
+// verifyKey returns the key used to verify signatures
+func (k *SigningKey) verifyKey() interface{} {
+	if k.IsAsymmetric() {
+		return k.PublicKey
+	}
+	return k.PrivateKey
+}
+
+// sign signs claims with the key and sets the kid header
+func (k *SigningKey) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(k.Method, claims)
+	if k.KID != "" {
+		token.Header["kid"] = k.KID
+	}
+	return token.SignedString(k.PrivateKey)
+}
@@ -1,30 +1,141 @@
 package jwt
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/JokerTrickster/common/auth"
 	_error "github.com/JokerTrickster/common/error"
+	_middleware "github.com/JokerTrickster/common/middleware"
 	"github.com/labstack/echo/v4"
 )
 
+// CheckerConfig defines the configuration for TokenCheckerWithConfig
+type CheckerConfig struct {
+	// TokenLookup is a comma separated list of "<source>:<name>[:<scheme>]" tried in order.
+	// Sources: header, cookie, query (ex. for WebSocket upgrades that cannot set headers)
+	// ex) "header:Authorization:Bearer,header:tkn,cookie:access_token,query:token"
+	TokenLookup string
+	// AllowAnonymous lets requests without a token through so public endpoints
+	// can personalize for logged-in users. Invalid tokens are still rejected.
+	AllowAnonymous bool
+}
+
+// DefaultCheckerConfig reads "Authorization: Bearer" first and falls back to the legacy tkn header
+var DefaultCheckerConfig = CheckerConfig{
+	TokenLookup: "header:Authorization:Bearer,header:tkn",
+}
+
+type tokenExtractor func(c echo.Context) string
+
 // TokenChecker checks the JWT token in the request header
 func TokenChecker(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		ctx := c.Request().Context()
-		accessToken := c.Request().Header.Get("tkn")
-		if accessToken == "" {
-			return _error.CreateError(ctx, string(_error.ErrBadParameter), _error.Trace(), "no access token in header", string(_error.ErrFromClient))
+	return TokenCheckerWithConfig(DefaultCheckerConfig)(next)
+}
+
+// OptionalTokenChecker authenticates the user when a token is present and lets anonymous requests through
+func OptionalTokenChecker(next echo.HandlerFunc) echo.HandlerFunc {
+	cfg := DefaultCheckerConfig
+	cfg.AllowAnonymous = true
+	return TokenCheckerWithConfig(cfg)(next)
+}
+
+// TokenCheckerWithConfig returns a token checker using the configured lookup chain
+func TokenCheckerWithConfig(cfg CheckerConfig) echo.MiddlewareFunc {
+	if cfg.TokenLookup == "" {
+		cfg.TokenLookup = DefaultCheckerConfig.TokenLookup
+	}
+	extractors, err := createExtractors(cfg.TokenLookup)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			var accessToken string
+			for _, extractor := range extractors {
+				if accessToken = extractor(c); accessToken != "" {
+					break
+				}
+			}
+			if accessToken == "" {
+				if cfg.AllowAnonymous {
+					return next(c)
+				}
+				return _error.CreateError(ctx, string(_error.ErrBadParameter), _error.Trace(), "no access token in request", string(_error.ErrFromClient))
+			}
+
+			claims, err := Verify(ctx, accessToken)
+			if err != nil {
+				return err
+			}
+
+			auth.SetEcho(c, &auth.Principal{
+				UserID:   claims.UserID,
+				Email:    claims.Email,
+				Roles:    claims.Roles,
+				Scopes:   claims.Scopes,
+				Provider: claims.Provider,
+				TokenID:  claims.Id,
+			})
+			// Deprecated: use auth.FromEcho / auth.FromContext
+			c.Set("uID", claims.UserID)
+			c.Set("email", claims.Email)
+			return next(c)
 		}
+	}
+}
 
-		if err := VerifyToken(accessToken); err != nil {
-			return err
+// createExtractors parses the TokenLookup string
+func createExtractors(lookup string) ([]tokenExtractor, error) {
+	var extractors []tokenExtractor
+	for _, source := range strings.Split(lookup, ",") {
+		parts := strings.Split(strings.TrimSpace(source), ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid token lookup: %s", source)
+		}
+		name := parts[1]
+		scheme := ""
+		if len(parts) == 3 {
+			scheme = parts[2]
 		}
 
-		uID, email, err := ParseToken(accessToken)
-		if err != nil {
-			return err
+		switch parts[0] {
+		case "header":
+			extractors = append(extractors, headerExtractor(name, scheme))
+		case "cookie":
+			extractors = append(extractors, func(c echo.Context) string {
+				cookie, err := c.Cookie(name)
+				if err != nil {
+					return ""
+				}
+				return cookie.Value
+			})
+		case "query":
+			// 쿼리 토큰이 요청 로그에 남지 않도록 등록합니다.
+			_middleware.RedactQueryParams(name)
+			extractors = append(extractors, func(c echo.Context) string {
+				return c.QueryParam(name)
+			})
+		default:
+			return nil, fmt.Errorf("invalid token lookup source: %s", parts[0])
 		}
+	}
+	return extractors, nil
+}
 
-		c.Set("uID", uID)
-		c.Set("email", email)
-		return next(c)
+// headerExtractor reads a header and strips the optional auth scheme
+func headerExtractor(name, scheme string) tokenExtractor {
+	return func(c echo.Context) string {
+		value := c.Request().Header.Get(name)
+		if scheme == "" {
+			return value
+		}
+		prefix := scheme + " "
+		if len(value) > len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+			return strings.TrimSpace(value[len(prefix):])
+		}
+		return ""
 	}
 }
@@ -0,0 +1,181 @@
+package jwt
+
+/*
+	리프레시 토큰 로테이션 및 재사용 탐지
+	- 리프레시 토큰은 한 번만 사용할 수 있고, 사용할 때마다 같은 family 로 새 토큰을 발급합니다.
+	- 이미 사용된 토큰이 다시 들어오면 탈취로 간주하고 family 전체를 폐기합니다.
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	_error "github.com/JokerTrickster/common/error"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRefreshTokenNotFound is returned by stores for unknown token IDs
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	// ErrRefreshTokenUsed is returned by stores when the token was already consumed
+	ErrRefreshTokenUsed = errors.New("refresh token already used")
+)
+
+// RefreshTokenRecord is the server-side state of an issued refresh token
+type RefreshTokenRecord struct {
+	TokenID   string `json:"tokenID"`
+	FamilyID  string `json:"familyID"`
+	UserID    uint   `json:"userID"`
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// RefreshTokenStore persists refresh token state
+type RefreshTokenStore interface {
+	// Save stores a newly issued refresh token
+	Save(ctx context.Context, record RefreshTokenRecord) error
+	// Consume atomically marks the token as used.
+	// It returns ErrRefreshTokenUsed if it was used before and ErrRefreshTokenNotFound if unknown.
+	Consume(ctx context.Context, tokenID string) error
+	// RevokeFamily revokes every token of the family
+	RevokeFamily(ctx context.Context, familyID string) error
+	// IsFamilyRevoked reports whether the family was revoked
+	IsFamilyRevoked(ctx context.Context, familyID string) (bool, error)
+}
+
+// TokenPair is an access/refresh token pair
+type TokenPair struct {
+	AccessToken      string `json:"accessToken"`
+	AccessExpiredAt  int64  `json:"accessExpiredAt"`
+	RefreshToken     string `json:"refreshToken"`
+	RefreshExpiredAt int64  `json:"refreshExpiredAt"`
+}
+
+// RefreshManager issues and rotates one-time refresh tokens
+type RefreshManager struct {
+	store RefreshTokenStore
+}
+
+// NewRefreshManager creates a refresh manager backed by store
+func NewRefreshManager(store RefreshTokenStore) *RefreshManager {
+	return &RefreshManager{store: store}
+}
+
+// Issue issues a token pair that starts a new token family (ex. on login)
+func (m *RefreshManager) Issue(ctx context.Context, email string, userID uint, opts ...TokenOption) (*TokenPair, error) {
+	return m.issue(ctx, email, userID, uuid.New().String(), opts...)
+}
+
+// Rotate consumes refreshToken and issues a new pair in the same family.
+// The registered claims are validated like Verify. Reusing a consumed token revokes
+// the whole family, and tokens revoked by the denylist are rejected.
+func (m *RefreshManager) Rotate(ctx context.Context, refreshToken string, opts ...TokenOption) (*TokenPair, error) {
+	claims := &JwtCustomClaims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(refreshToken, claims, refreshKeyFunc)
+	if err != nil || !token.Valid {
+		return nil, _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "invalid refresh token", string(_error.ErrFromClient))
+	}
+	if err := validateClaims(ctx, claims, time.Now()); err != nil {
+		return nil, err
+	}
+	if claims.Id == "" || claims.FamilyID == "" {
+		return nil, _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "refresh token is not rotatable", string(_error.ErrFromClient))
+	}
+	if err := checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	revoked, err := m.store.IsFamilyRevoked(ctx, claims.FamilyID)
+	if err != nil {
+		return nil, _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to check refresh token family", string(_error.ErrFromInternal))
+	}
+	if revoked {
+		return nil, _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "refresh token family revoked", string(_error.ErrFromClient))
+	}
+
+	if err := m.store.Consume(ctx, claims.Id); err != nil {
+		switch {
+		case errors.Is(err, ErrRefreshTokenUsed):
+			// 재사용 탐지: family 전체 폐기
+			if revokeErr := m.store.RevokeFamily(ctx, claims.FamilyID); revokeErr != nil {
+				return nil, _error.Wrap(ctx, revokeErr, string(_error.ErrInternalServer), _error.Trace(), "failed to revoke refresh token family", string(_error.ErrFromInternal))
+			}
+			return nil, _error.CreateError(ctx, string(_error.ErrRefreshTokenReused), _error.Trace(), fmt.Sprintf("refresh token reused, family %s revoked", claims.FamilyID), string(_error.ErrFromClient))
+		case errors.Is(err, ErrRefreshTokenNotFound):
+			return nil, _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "unknown refresh token", string(_error.ErrFromClient))
+		}
+		return nil, _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to consume refresh token", string(_error.ErrFromInternal))
+	}
+
+	// 기존 권한 정보를 유지
+	opts = append([]TokenOption{WithRoles(claims.Roles...), WithScopes(claims.Scopes...), WithProvider(claims.Provider)}, opts...)
+	return m.issue(ctx, claims.Email, claims.UserID, claims.FamilyID, opts...)
+}
+
+// RevokeFamily revokes every refresh token of a family (ex. on logout)
+func (m *RefreshManager) RevokeFamily(ctx context.Context, familyID string) error {
+	if err := m.store.RevokeFamily(ctx, familyID); err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to revoke refresh token family", string(_error.ErrFromInternal))
+	}
+	return nil
+}
+
+// issue generates a token pair and stores the refresh token
+func (m *RefreshManager) issue(ctx context.Context, email string, userID uint, familyID string, opts ...TokenOption) (*TokenPair, error) {
+	now := time.Now()
+	accessToken, accessExpiredAt, err := GenerateAccessToken(email, now, userID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenID := uuid.New().String()
+	refreshOpts := append(append([]TokenOption{}, opts...), withTokenID(tokenID), withFamilyID(familyID))
+	refreshToken, refreshExpiredAt, err := GenerateRefreshToken(email, now, userID, refreshOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.store.Save(ctx, RefreshTokenRecord{
+		TokenID:   tokenID,
+		FamilyID:  familyID,
+		UserID:    userID,
+		Token:     refreshToken,
+		ExpiresAt: refreshExpiredAt,
+	})
+	if err != nil {
+		return nil, _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to save refresh token", string(_error.ErrFromInternal))
+	}
+
+	return &TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiredAt:  accessExpiredAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiredAt: refreshExpiredAt,
+	}, nil
+}
+
+// withTokenID sets the jti claim
+func withTokenID(tokenID string) TokenOption {
+	return func(c *JwtCustomClaims) {
+		c.Id = tokenID
+	}
+}
+
+// withFamilyID sets the refresh token family claim
+func withFamilyID(familyID string) TokenOption {
+	return func(c *JwtCustomClaims) {
+		c.FamilyID = familyID
+	}
+}
+
+// refreshKeyFunc verifies refresh tokens with the refresh secret
+func refreshKeyFunc(token *jwt.Token) (interface{}, error) {
+	if token.Method.Alg() != jwt.SigningMethodHS256.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return RefreshTokenSecretKey, nil
+}
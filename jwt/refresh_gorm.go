@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"context"
+
+	"github.com/JokerTrickster/common/db/mysql"
+	"gorm.io/gorm"
+)
+
+// GormRefreshTokenStore stores refresh token state in the mysql.Tokens table
+type GormRefreshTokenStore struct {
+	db *gorm.DB
+}
+
+// NewGormRefreshTokenStore creates a MySQL-backed refresh token store
+func NewGormRefreshTokenStore(db *gorm.DB) *GormRefreshTokenStore {
+	return &GormRefreshTokenStore{db: db}
+}
+
+// Save inserts a token row
+func (s *GormRefreshTokenStore) Save(ctx context.Context, record RefreshTokenRecord) error {
+	return s.db.WithContext(ctx).Create(&mysql.Tokens{
+		UserID:           record.UserID,
+		RefreshToken:     record.Token,
+		RefreshExpiredAt: record.ExpiresAt,
+		TokenID:          record.TokenID,
+		FamilyID:         record.FamilyID,
+	}).Error
+}
+
+// Consume flips used with a conditional update so only one caller succeeds
+func (s *GormRefreshTokenStore) Consume(ctx context.Context, tokenID string) error {
+	result := s.db.WithContext(ctx).Model(&mysql.Tokens{}).
+		Where("token_id = ? AND used = ?", tokenID, false).
+		Update("used", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&mysql.Tokens{}).Where("token_id = ?", tokenID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrRefreshTokenNotFound
+	}
+	return ErrRefreshTokenUsed
+}
+
+// RevokeFamily marks every row of the family as revoked
+func (s *GormRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.db.WithContext(ctx).Model(&mysql.Tokens{}).
+		Where("family_id = ?", familyID).
+		Update("revoked", true).Error
+}
+
+// IsFamilyRevoked reports whether any row of the family is revoked
+func (s *GormRefreshTokenStore) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&mysql.Tokens{}).
+		Where("family_id = ? AND revoked = ?", familyID, true).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRefreshTokenStore stores refresh token state in Redis
+type RedisRefreshTokenStore struct {
+	service *_redis.RedisService
+}
+
+// NewRedisRefreshTokenStore creates a Redis-backed refresh token store
+func NewRedisRefreshTokenStore(service *_redis.RedisService) *RedisRefreshTokenStore {
+	return &RedisRefreshTokenStore{service: service}
+}
+
+// Save stores the record until the token expires
+func (s *RedisRefreshTokenStore) Save(ctx context.Context, record RefreshTokenRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.service.SetKey(ctx, _redis.AuthRefreshTokenKey+record.TokenID, data, ttlUntil(record.ExpiresAt))
+}
+
+// Consume marks the token as used with SETNX so only one caller succeeds
+func (s *RedisRefreshTokenStore) Consume(ctx context.Context, tokenID string) error {
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := client.Get(ctx, _redis.AuthRefreshTokenKey+tokenID).Bytes()
+	if err == redis.Nil {
+		return ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return err
+	}
+	var record RefreshTokenRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return err
+	}
+
+	ok, err := client.SetNX(ctx, _redis.AuthRefreshUsedKey+tokenID, 1, ttlUntil(record.ExpiresAt)).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+// RevokeFamily marks the family as revoked for the refresh token lifetime
+func (s *RedisRefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	return s.service.SetKey(ctx, _redis.AuthRefreshFamilyKey+familyID, 1, config.RefreshTTL)
+}
+
+// IsFamilyRevoked reports whether the family was revoked
+func (s *RedisRefreshTokenStore) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	_, err := s.service.GetKey(ctx, _redis.AuthRefreshFamilyKey+familyID)
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ttlUntil returns the duration until the epoch second, at least one second
+func ttlUntil(expiresAt int64) time.Duration {
+	ttl := time.Until(time.Unix(expiresAt, 0))
+	if ttl < time.Second {
+		return time.Second
+	}
+	return ttl
+}
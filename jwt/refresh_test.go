@@ -0,0 +1,250 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+// memRefreshStore is an in-memory RefreshTokenStore for tests
+type memRefreshStore struct {
+	mu       sync.Mutex
+	records  map[string]RefreshTokenRecord
+	used     map[string]bool
+	families map[string]bool
+}
+
+func newMemRefreshStore() *memRefreshStore {
+	return &memRefreshStore{records: map[string]RefreshTokenRecord{}, used: map[string]bool{}, families: map[string]bool{}}
+}
+
+func (s *memRefreshStore) Save(ctx context.Context, record RefreshTokenRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[record.TokenID] = record
+	return nil
+}
+
+func (s *memRefreshStore) Consume(ctx context.Context, tokenID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[tokenID]; !ok {
+		return ErrRefreshTokenNotFound
+	}
+	if s.used[tokenID] {
+		return ErrRefreshTokenUsed
+	}
+	s.used[tokenID] = true
+	return nil
+}
+
+func (s *memRefreshStore) RevokeFamily(ctx context.Context, familyID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.families[familyID] = true
+	return nil
+}
+
+func (s *memRefreshStore) IsFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.families[familyID], nil
+}
+
+// memDenylist is an in-memory Denylist for tests
+type memDenylist struct {
+	mu     sync.Mutex
+	tokens map[string]bool
+	users  map[uint]int64
+}
+
+func (d *memDenylist) RevokeToken(ctx context.Context, tokenID string, expiresAt int64) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.tokens[tokenID] = true
+	return nil
+}
+
+func (d *memDenylist) RevokeUserTokensBefore(ctx context.Context, userID uint, before time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.users[userID] = before.UnixMilli()
+	return nil
+}
+
+func (d *memDenylist) IsRevoked(ctx context.Context, claims *JwtCustomClaims) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.tokens[claims.Id] || issuedBefore(claims, d.users[claims.UserID]), nil
+}
+
+// setupRefreshTest initializes local secrets and an empty denylist
+func setupRefreshTest(t *testing.T) *RefreshManager {
+	t.Helper()
+	if err := InitJWTWithOptions(context.Background(), WithLocal(true)); err != nil {
+		t.Fatalf("InitJWTWithOptions() error = %v", err)
+	}
+	SetDenylist(&memDenylist{tokens: map[string]bool{}, users: map[uint]int64{}})
+	t.Cleanup(func() { SetDenylist(nil) })
+	return NewRefreshManager(newMemRefreshStore())
+}
+
+func TestRefreshManagerRotate(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, m *RefreshManager, pair *TokenPair) string // returns the token to rotate
+		wantErr _error.ErrType
+	}{
+		{
+			name:    "valid token",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string { return pair.RefreshToken },
+		},
+		{
+			name:    "malformed token",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string { return "not-a-token" },
+			wantErr: _error.ErrBadToken,
+		},
+		{
+			name: "reused token",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				if _, err := m.Rotate(ctx, pair.RefreshToken); err != nil {
+					t.Fatalf("first Rotate() error = %v", err)
+				}
+				return pair.RefreshToken
+			},
+			wantErr: _error.ErrRefreshTokenReused,
+		},
+		{
+			name: "successor after reuse",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				next, err := m.Rotate(ctx, pair.RefreshToken)
+				if err != nil {
+					t.Fatalf("first Rotate() error = %v", err)
+				}
+				if _, err := m.Rotate(ctx, pair.RefreshToken); !errors.Is(err, _error.ErrRefreshTokenReused) {
+					t.Fatalf("reuse Rotate() error = %v, want %s", err, _error.ErrRefreshTokenReused)
+				}
+				return next.RefreshToken
+			},
+			wantErr: _error.ErrBadToken,
+		},
+		{
+			name: "unexpected issuer",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				if err := InitJWTWithOptions(ctx, WithLocal(true), WithIssuer("other-service")); err != nil {
+					t.Fatalf("InitJWTWithOptions() error = %v", err)
+				}
+				return pair.RefreshToken
+			},
+			wantErr: _error.ErrBadToken,
+		},
+		{
+			name: "unexpected audience",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				if err := InitJWTWithOptions(ctx, WithLocal(true), WithAudience("other-client")); err != nil {
+					t.Fatalf("InitJWTWithOptions() error = %v", err)
+				}
+				return pair.RefreshToken
+			},
+			wantErr: _error.ErrBadToken,
+		},
+		{
+			name: "user revoked after issue",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				time.Sleep(2 * time.Millisecond)
+				if err := RevokeAllForUser(ctx, 1, time.Now()); err != nil {
+					t.Fatalf("RevokeAllForUser() error = %v", err)
+				}
+				return pair.RefreshToken
+			},
+			wantErr: _error.ErrTokenRevoked,
+		},
+		{
+			name: "login right after user revocation",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				if err := RevokeAllForUser(ctx, 1, time.Now()); err != nil {
+					t.Fatalf("RevokeAllForUser() error = %v", err)
+				}
+				time.Sleep(2 * time.Millisecond)
+				next, err := m.Issue(ctx, "user@example.com", 1, WithRoles("admin"))
+				if err != nil {
+					t.Fatalf("Issue() error = %v", err)
+				}
+				return next.RefreshToken
+			},
+		},
+		{
+			name: "user revoked before issue",
+			prepare: func(t *testing.T, m *RefreshManager, pair *TokenPair) string {
+				if err := RevokeAllForUser(ctx, 1, time.Now().Add(-time.Hour)); err != nil {
+					t.Fatalf("RevokeAllForUser() error = %v", err)
+				}
+				return pair.RefreshToken
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := setupRefreshTest(t)
+			pair, err := m.Issue(ctx, "user@example.com", 1, WithRoles("admin"))
+			if err != nil {
+				t.Fatalf("Issue() error = %v", err)
+			}
+
+			got, err := m.Rotate(ctx, tt.prepare(t, m, pair))
+			if tt.wantErr != "" {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Rotate() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Rotate() error = %v", err)
+			}
+			if got.RefreshToken == pair.RefreshToken {
+				t.Error("Rotate() returned the same refresh token")
+			}
+			claims, err := ParseClaims(got.AccessToken)
+			if err != nil {
+				t.Fatalf("ParseClaims() error = %v", err)
+			}
+			if len(claims.Roles) != 1 || claims.Roles[0] != "admin" {
+				t.Errorf("roles = %v, want [admin]", claims.Roles)
+			}
+		})
+	}
+}
+
+func TestRefreshManagerRotateConcurrent(t *testing.T) {
+	ctx := context.Background()
+	m := setupRefreshTest(t)
+	pair, err := m.Issue(ctx, "user@example.com", 1)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	rotated := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Rotate(ctx, pair.RefreshToken); err == nil {
+				mu.Lock()
+				rotated++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if rotated != 1 {
+		t.Errorf("successful rotations = %d, want 1", rotated)
+	}
+}
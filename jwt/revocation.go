@@ -0,0 +1,229 @@
+package jwt
+
+/*
+	액세스 토큰 폐기 (denylist)
+	- 토큰 단위 폐기 (jti)
+	- 사용자 단위 폐기 (특정 시각 이전에 발급된 모든 토큰)
+*/
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	_error "github.com/JokerTrickster/common/error"
+	"github.com/redis/go-redis/v9"
+)
+
+// Denylist stores revoked access tokens
+type Denylist interface {
+	// RevokeToken revokes a single token until it expires
+	RevokeToken(ctx context.Context, tokenID string, expiresAt int64) error
+	// RevokeUserTokensBefore revokes every token of the user issued before the given time
+	RevokeUserTokensBefore(ctx context.Context, userID uint, before time.Time) error
+	// IsRevoked reports whether the token was revoked
+	IsRevoked(ctx context.Context, claims *JwtCustomClaims) (bool, error)
+}
+
+var denylist Denylist
+
+// SetDenylist sets the denylist consulted by TokenChecker
+func SetDenylist(d Denylist) {
+	denylist = d
+}
+
+// RevokeAccessToken revokes a single access token by its jti
+func RevokeAccessToken(ctx context.Context, tokenID string, expiresAt int64) error {
+	if denylist == nil {
+		return _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "denylist is not configured", string(_error.ErrFromInternal))
+	}
+	if err := denylist.RevokeToken(ctx, tokenID, expiresAt); err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to revoke token", string(_error.ErrFromRedis))
+	}
+	return nil
+}
+
+// RevokeAllForUser revokes every access and refresh token of the user issued before the given time (ex. ban).
+// The cutoff has millisecond precision, so a login right after the revocation is accepted.
+// Tokens without iatMs only carry iat seconds and are revoked through the end of that second.
+func RevokeAllForUser(ctx context.Context, userID uint, before time.Time) error {
+	if denylist == nil {
+		return _error.CreateError(ctx, string(_error.ErrInternalServer), _error.Trace(), "denylist is not configured", string(_error.ErrFromInternal))
+	}
+	if err := denylist.RevokeUserTokensBefore(ctx, userID, before); err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to revoke user tokens", string(_error.ErrFromRedis))
+	}
+	return nil
+}
+
+// checkRevoked returns ErrTokenRevoked when the denylist contains the token
+func checkRevoked(ctx context.Context, claims *JwtCustomClaims) error {
+	if denylist == nil {
+		return nil
+	}
+	revoked, err := denylist.IsRevoked(ctx, claims)
+	if err != nil {
+		return _error.Wrap(ctx, err, string(_error.ErrInternalServer), _error.Trace(), "failed to check token revocation", string(_error.ErrFromRedis))
+	}
+	if revoked {
+		return _error.CreateError(ctx, string(_error.ErrTokenRevoked), _error.Trace(), "token revoked", string(_error.ErrFromClient))
+	}
+	return nil
+}
+
+// RedisDenylist is a Redis-backed denylist with a short in-memory cache
+type RedisDenylist struct {
+	service  *_redis.RedisService
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	tokens map[string]cachedValue
+	users  map[uint]cachedValue
+}
+
+// legacyCutoffLimit separates cutoffs stored in epoch seconds by older versions from milliseconds
+const legacyCutoffLimit = 100000000000
+
+type cachedValue struct {
+	value     int64 // token: 1 if revoked, user: revoke-before epoch millisecond
+	expiresAt time.Time
+}
+
+// NewRedisDenylist creates a denylist. Lookups are cached locally for cacheTTL (ex. 5s).
+func NewRedisDenylist(service *_redis.RedisService, cacheTTL time.Duration) *RedisDenylist {
+	return &RedisDenylist{
+		service:  service,
+		cacheTTL: cacheTTL,
+		tokens:   map[string]cachedValue{},
+		users:    map[uint]cachedValue{},
+	}
+}
+
+// RevokeToken stores the jti until the token expires
+func (d *RedisDenylist) RevokeToken(ctx context.Context, tokenID string, expiresAt int64) error {
+	if err := d.service.SetKey(ctx, _redis.AuthRevokedTokenKey+tokenID, 1, ttlUntil(expiresAt)); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.tokens[tokenID] = cachedValue{value: 1, expiresAt: time.Now().Add(d.cacheTTL)}
+	d.mu.Unlock()
+	return nil
+}
+
+// RevokeUserTokensBefore stores the cutoff until every token issued before it has expired
+func (d *RedisDenylist) RevokeUserTokensBefore(ctx context.Context, userID uint, before time.Time) error {
+	key := _redis.AuthRevokedUserKey + strconv.FormatUint(uint64(userID), 10)
+	ttl := config.AccessTTL
+	if config.RefreshTTL > ttl {
+		ttl = config.RefreshTTL
+	}
+	if err := d.service.SetKey(ctx, key, before.UnixMilli(), ttl); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.users[userID] = cachedValue{value: before.UnixMilli(), expiresAt: time.Now().Add(d.cacheTTL)}
+	d.mu.Unlock()
+	return nil
+}
+
+// IsRevoked checks the jti and the user cutoff
+func (d *RedisDenylist) IsRevoked(ctx context.Context, claims *JwtCustomClaims) (bool, error) {
+	if claims.Id != "" {
+		revoked, err := d.lookupToken(ctx, claims.Id)
+		if err != nil || revoked {
+			return revoked, err
+		}
+	}
+	before, err := d.lookupUser(ctx, claims.UserID)
+	if err != nil {
+		return false, err
+	}
+	return issuedBefore(claims, before), nil
+}
+
+// issuedBefore reports whether the token was issued before the cutoff in epoch milliseconds
+func issuedBefore(claims *JwtCustomClaims, beforeMs int64) bool {
+	if beforeMs <= 0 {
+		return false
+	}
+	if claims.IssuedAtMs > 0 {
+		return claims.IssuedAtMs < beforeMs
+	}
+	// iat 초 단위 토큰은 같은 초에 발급된 경우도 폐기합니다.
+	return claims.IssuedAt*1000 <= beforeMs
+}
+
+// lookupToken returns whether the jti is revoked, using the local cache first
+func (d *RedisDenylist) lookupToken(ctx context.Context, tokenID string) (bool, error) {
+	now := time.Now()
+	d.mu.Lock()
+	if cached, ok := d.tokens[tokenID]; ok && now.Before(cached.expiresAt) {
+		d.mu.Unlock()
+		return cached.value == 1, nil
+	}
+	d.mu.Unlock()
+
+	var value int64
+	_, err := d.service.GetKey(ctx, _redis.AuthRevokedTokenKey+tokenID)
+	if err == nil {
+		value = 1
+	} else if err != redis.Nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	d.tokens[tokenID] = cachedValue{value: value, expiresAt: now.Add(d.cacheTTL)}
+	d.pruneLocked(now)
+	d.mu.Unlock()
+	return value == 1, nil
+}
+
+// lookupUser returns the revoke-before epoch millisecond of the user, 0 if none
+func (d *RedisDenylist) lookupUser(ctx context.Context, userID uint) (int64, error) {
+	now := time.Now()
+	d.mu.Lock()
+	if cached, ok := d.users[userID]; ok && now.Before(cached.expiresAt) {
+		d.mu.Unlock()
+		return cached.value, nil
+	}
+	d.mu.Unlock()
+
+	var before int64
+	value, err := d.service.GetKey(ctx, _redis.AuthRevokedUserKey+strconv.FormatUint(uint64(userID), 10))
+	if err == nil {
+		before, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if before < legacyCutoffLimit {
+			// 이전 버전이 저장한 초 단위 값은 해당 초의 끝까지 폐기합니다.
+			before = (before + 1) * 1000
+		}
+	} else if err != redis.Nil {
+		return 0, err
+	}
+
+	d.mu.Lock()
+	d.users[userID] = cachedValue{value: before, expiresAt: now.Add(d.cacheTTL)}
+	d.mu.Unlock()
+	return before, nil
+}
+
+// pruneLocked drops expired cache entries once the cache grows large
+func (d *RedisDenylist) pruneLocked(now time.Time) {
+	if len(d.tokens)+len(d.users) < 10000 {
+		return
+	}
+	for key, cached := range d.tokens {
+		if now.After(cached.expiresAt) {
+			delete(d.tokens, key)
+		}
+	}
+	for key, cached := range d.users {
+		if now.After(cached.expiresAt) {
+			delete(d.users, key)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package jwt
+
+import (
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestIssuedBefore(t *testing.T) {
+	const cutoff = int64(1700000000500) // 1700000000.5s
+	tests := []struct {
+		name   string
+		claims JwtCustomClaims
+		before int64
+		want   bool
+	}{
+		{name: "no cutoff", claims: JwtCustomClaims{IssuedAtMs: cutoff - 1}, want: false},
+		{name: "issued before the cutoff", claims: JwtCustomClaims{IssuedAtMs: cutoff - 1}, before: cutoff, want: true},
+		{name: "issued at the cutoff", claims: JwtCustomClaims{IssuedAtMs: cutoff}, before: cutoff, want: false},
+		{name: "issued later in the same second", claims: JwtCustomClaims{IssuedAtMs: cutoff + 100}, before: cutoff, want: false},
+		{name: "legacy token in the same second", claims: JwtCustomClaims{StandardClaims: jwt.StandardClaims{IssuedAt: 1700000000}}, before: cutoff, want: true},
+		{name: "legacy token in the next second", claims: JwtCustomClaims{StandardClaims: jwt.StandardClaims{IssuedAt: 1700000001}}, before: cutoff, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issuedBefore(&tt.claims, tt.before); got != tt.want {
+				t.Errorf("issuedBefore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
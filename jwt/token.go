@@ -11,42 +11,79 @@ import (
 
 	_error "github.com/JokerTrickster/common/error"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
 )
 
 type JwtCustomClaims struct {
-	CreateTime int64  `json:"createTime"`
-	UserID     uint   `json:"userID"`
-	Email      string `json:"email"`
+	CreateTime int64    `json:"createTime"`
+	UserID     uint     `json:"userID"`
+	Email      string   `json:"email"`
+	Roles      []string `json:"roles,omitempty"`
+	Scopes     []string `json:"scopes,omitempty"`
+	Provider   string   `json:"provider,omitempty"`
+	FamilyID   string   `json:"fid,omitempty"`
+	IssuedAtMs int64    `json:"iatMs,omitempty"` // issue time in milliseconds for revocation cutoffs
 	jwt.StandardClaims
 }
 
+// TokenOption customizes the claims of a generated token
+type TokenOption func(*JwtCustomClaims)
+
+// WithRoles sets the roles claim (ex. "admin", "user")
+func WithRoles(roles ...string) TokenOption {
+	return func(c *JwtCustomClaims) {
+		c.Roles = roles
+	}
+}
+
+// WithScopes sets the scopes claim (ex. "food:write")
+func WithScopes(scopes ...string) TokenOption {
+	return func(c *JwtCustomClaims) {
+		c.Scopes = scopes
+	}
+}
+
+// WithProvider sets the login provider claim (ex. "google", "kakao")
+func WithProvider(provider string) TokenOption {
+	return func(c *JwtCustomClaims) {
+		c.Provider = provider
+	}
+}
+
 // GenerateToken generates access and refresh tokens
-func GenerateToken(email string, userID uint) (string, int64, string, int64, error) {
+func GenerateToken(email string, userID uint, opts ...TokenOption) (string, int64, string, int64, error) {
 	now := time.Now()
-	accessToken, accessTknExpiredAt, err := GenerateAccessToken(email, now, userID)
+	accessToken, accessTknExpiredAt, err := GenerateAccessToken(email, now, userID, opts...)
 	if err != nil {
 		return "", 0, "", 0, err
 	}
-	refreshToken, refreshTknExpiredAt, err := GenerateRefreshToken(email, now, userID)
+	refreshToken, refreshTknExpiredAt, err := GenerateRefreshToken(email, now, userID, opts...)
 	if err != nil {
 		return "", 0, "", 0, err
 	}
 	return accessToken, accessTknExpiredAt, refreshToken, refreshTknExpiredAt, nil
 }
 
-// GenerateAccessToken generates an access token
-func GenerateAccessToken(email string, now time.Time, userID uint) (string, int64, error) {
-	expiredAt := now.Add(time.Hour * AccessTokenExpiredTime).Unix()
+// GenerateAccessToken generates an access token with a unique jti
+func GenerateAccessToken(email string, now time.Time, userID uint, opts ...TokenOption) (string, int64, error) {
+	expiredAt := now.Add(config.AccessTTL).Unix()
 	claims := &JwtCustomClaims{
 		CreateTime: now.Unix(),
 		UserID:     userID,
 		Email:      email,
+		IssuedAtMs: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
+			Id:        uuid.New().String(),
 			ExpiresAt: expiredAt,
+			IssuedAt:  now.Unix(),
+			Issuer:    config.Issuer,
+			Audience:  config.Audience,
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	accessToken, err := token.SignedString(AccessTokenSecretKey)
+	for _, opt := range opts {
+		opt(claims)
+	}
+	accessToken, err := accessSigningKey().sign(claims)
 	if err != nil {
 		return "", 0, err
 	}
@@ -54,16 +91,23 @@ func GenerateAccessToken(email string, now time.Time, userID uint) (string, int6
 }
 
 // GenerateRefreshToken generates a refresh token
-func GenerateRefreshToken(email string, now time.Time, userID uint) (string, int64, error) {
-	expiredAt := now.Add(time.Hour * RefreshTokenExpiredTime).Unix()
+func GenerateRefreshToken(email string, now time.Time, userID uint, opts ...TokenOption) (string, int64, error) {
+	expiredAt := now.Add(config.RefreshTTL).Unix()
 	claims := &JwtCustomClaims{
 		CreateTime: now.Unix(),
 		UserID:     userID,
 		Email:      email,
+		IssuedAtMs: now.UnixMilli(),
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: expiredAt,
+			IssuedAt:  now.Unix(),
+			Issuer:    config.Issuer,
+			Audience:  config.Audience,
 		},
 	}
+	for _, opt := range opts {
+		opt(claims)
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	refreshToken, err := token.SignedString(RefreshTokenSecretKey)
 	if err != nil {
@@ -74,23 +118,46 @@ func GenerateRefreshToken(email string, now time.Time, userID uint) (string, int
 
 // VerifyToken verifies the validity of a JWT
 func VerifyToken(tokenString string) error {
-	token, err := jwt.ParseWithClaims(tokenString, &JwtCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return AccessTokenSecretKey, nil
-	})
-	if err != nil || !token.Valid {
-		return _error.CreateError(context.TODO(), string(_error.ErrBadToken), _error.Trace(), "invalid token", string(_error.ErrFromClient))
-	}
-	return nil
+	_, err := Verify(context.TODO(), tokenString)
+	return err
 }
 
 // ParseToken extracts claims from a JWT
 func ParseToken(tokenString string) (uint, string, error) {
-	token, _ := jwt.ParseWithClaims(tokenString, &JwtCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return AccessTokenSecretKey, nil
-	})
-	claims, ok := token.Claims.(*JwtCustomClaims)
-	if !ok {
-		return 0, "", _error.CreateError(context.TODO(), string(_error.ErrBadToken), _error.Trace(), "failed to extract claims", string(_error.ErrFromClient))
+	claims, err := Verify(context.TODO(), tokenString)
+	if err != nil {
+		return 0, "", err
 	}
 	return claims.UserID, claims.Email, nil
 }
+
+// ParseClaims extracts all custom claims from a verified JWT
+func ParseClaims(tokenString string) (*JwtCustomClaims, error) {
+	return Verify(context.TODO(), tokenString)
+}
+
+// accessKeyRing returns the configured key ring
+func accessKeyRing() *KeyRing {
+	if config.KeyRing != nil {
+		return config.KeyRing
+	}
+	return NewKeyRing(NewHMACKey("", AccessTokenSecretKey), 0)
+}
+
+// accessSigningKey returns the key used to sign new access tokens
+func accessSigningKey() *SigningKey {
+	return accessKeyRing().Active()
+}
+
+// accessKeyFunc resolves the verification key by kid and rejects unexpected algorithms
+func accessKeyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := accessKeyRing().Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID: %s", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.verifyKey(), nil
+}
@@ -1 +1,64 @@
 package jwt
+
+/*
+	액세스 토큰 검증 (서명, 알고리즘, 클레임)
+*/
+
+import (
+	"context"
+	"time"
+
+	_error "github.com/JokerTrickster/common/error"
+	"github.com/dgrijalva/jwt-go"
+)
+
+// Claims are the typed claims of a verified access token
+type Claims = JwtCustomClaims
+
+// Verify parses the token once, checks the signature with the expected algorithm,
+// validates exp/nbf/iat/iss/aud with the configured leeway and consults the denylist.
+// Expired tokens return ErrTokenExpired; any other failure returns ErrBadToken.
+func Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	token, err := verifyToken(ctx, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	return token.Claims.(*Claims), nil
+}
+
+// verifyToken runs the Verify checks and returns the parsed token with *Claims
+func verifyToken(ctx context.Context, tokenString string) (*jwt.Token, error) {
+	claims := &Claims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	token, err := parser.ParseWithClaims(tokenString, claims, accessKeyFunc)
+	if err != nil || !token.Valid {
+		return nil, _error.Wrap(ctx, err, string(_error.ErrBadToken), _error.Trace(), "invalid token", string(_error.ErrFromClient))
+	}
+	if err := validateClaims(ctx, claims, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := checkRevoked(ctx, claims); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// validateClaims checks the registered claims against the configuration
+func validateClaims(ctx context.Context, claims *Claims, now time.Time) error {
+	leeway := config.Leeway
+	switch {
+	case claims.ExpiresAt == 0:
+		return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "token has no expiration", string(_error.ErrFromClient))
+	case now.After(time.Unix(claims.ExpiresAt, 0).Add(leeway)):
+		return _error.CreateError(ctx, string(_error.ErrTokenExpired), _error.Trace(), "token expired", string(_error.ErrFromClient))
+	case claims.NotBefore != 0 && now.Add(leeway).Before(time.Unix(claims.NotBefore, 0)):
+		return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "token is not valid yet", string(_error.ErrFromClient))
+	case claims.IssuedAt != 0 && now.Add(leeway).Before(time.Unix(claims.IssuedAt, 0)):
+		return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "token issued in the future", string(_error.ErrFromClient))
+	case config.Issuer != "" && claims.Issuer != config.Issuer:
+		return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "unexpected token issuer", string(_error.ErrFromClient))
+	case config.Audience != "" && claims.Audience != config.Audience:
+		return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "unexpected token audience", string(_error.ErrFromClient))
+	}
+	return nil
+}
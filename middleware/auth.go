@@ -1 +1,87 @@
-package middleware
\ No newline at end of file
+package middleware
+
+/*
+	역할(Role) / 권한 범위(Scope) 기반 인가 미들웨어
+	jwt.TokenChecker 이후에 사용합니다.
+*/
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/JokerTrickster/common/auth"
+	_error "github.com/JokerTrickster/common/error"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequireRoles allows the request when the user has at least one of the given roles
+func RequireRoles(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			principal, ok := auth.FromEcho(c)
+			if !ok {
+				return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "not authenticated", string(_error.ErrFromClient))
+			}
+			if !hasAnyRole(principal, roles) {
+				return _error.CreateError(ctx, string(_error.ErrInsufficientRole), _error.Trace(), fmt.Sprintf("one of roles %v is required", roles), string(_error.ErrFromClient))
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireScopes allows the request only when the token has all of the given scopes
+func RequireScopes(scopes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			principal, ok := auth.FromEcho(c)
+			if !ok {
+				return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "not authenticated", string(_error.ErrFromClient))
+			}
+			for _, scope := range scopes {
+				if !principal.HasScope(scope) {
+					return _error.CreateError(ctx, string(_error.ErrInsufficientScope), _error.Trace(), fmt.Sprintf("scope %s is required", scope), string(_error.ErrFromClient))
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// RequireOwner allows the request when the path parameter equals the authenticated user ID.
+// Users with one of bypassRoles (ex. "admin") may access any resource.
+func RequireOwner(param string, bypassRoles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			principal, ok := auth.FromEcho(c)
+			if !ok {
+				return _error.CreateError(ctx, string(_error.ErrBadToken), _error.Trace(), "not authenticated", string(_error.ErrFromClient))
+			}
+			if hasAnyRole(principal, bypassRoles) {
+				return next(c)
+			}
+			ownerID, err := strconv.ParseUint(c.Param(param), 10, 64)
+			if err != nil {
+				return _error.CreateError(ctx, string(_error.ErrBadParameter), _error.Trace(), fmt.Sprintf("invalid path parameter %s", param), string(_error.ErrFromClient))
+			}
+			if uint(ownerID) != principal.UserID {
+				return _error.CreateError(ctx, string(_error.ErrNotResourceOwner), _error.Trace(), "resource belongs to another user", string(_error.ErrFromClient))
+			}
+			return next(c)
+		}
+	}
+}
+
+// hasAnyRole reports whether the principal has at least one of roles
+func hasAnyRole(principal *auth.Principal, roles []string) bool {
+	for _, role := range roles {
+		if principal.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	_error "github.com/JokerTrickster/common/error"
 	"github.com/JokerTrickster/common/logging"
 	"github.com/JokerTrickster/common/request"
 
@@ -35,6 +37,7 @@ func LoggingMiddleware(logger *logging.Logger) echo.MiddlewareFunc {
 				})
 				return c.JSON(400, map[string]string{"error": "Invalid request format"})
 			}
+			requestData.Query = redactQuery(requestData.Query)
 
 			// 다음 핸들러 실행
 			err := next(c)
@@ -69,15 +72,30 @@ func LoggingMiddleware(logger *logging.Logger) echo.MiddlewareFunc {
 // handleAndLogError handles and logs errors with structured parsing
 func handleAndLogError(c echo.Context, logger *logging.Logger, err error, requestData request.RequestData, latency time.Duration) {
 	var httpErrStruct HTTPErrorStruct
-	// 에러 메시지 분석
-	errMessage := err.Error()
-	parts := strings.SplitN(errMessage, ", ", 2)
-	if len(parts) == 2 {
-		// code=... 추출
-		fmt.Sscanf(parts[0], "code=%d", &httpErrStruct.Code)
+	var echoErr *echo.HTTPError
+	if appErr, ok := _error.AsAppError(err); ok {
+		// 공통 에러 타입은 파싱 없이 바로 사용
+		httpErrStruct.Code = appErr.HttpCode
+		if httpErrStruct.Code == 0 {
+			httpErrStruct.Code = _error.HttpCodeOf(appErr.ErrType)
+		}
+		httpErrStruct.Message = appErr.Error()
+	} else if errors.As(err, &echoErr) {
+		httpErrStruct.Code = echoErr.Code
+		httpErrStruct.Message = fmt.Sprintf("%v", echoErr.Message)
+	} else {
+		// 에러 메시지 분석
+		errMessage := err.Error()
+		parts := strings.SplitN(errMessage, ", ", 2)
+		if len(parts) == 2 {
+			// code=... 추출
+			fmt.Sscanf(parts[0], "code=%d", &httpErrStruct.Code)
 
-		// message=... 추출
-		httpErrStruct.Message = strings.TrimPrefix(parts[1], "message=")
+			// message=... 추출
+			httpErrStruct.Message = strings.TrimPrefix(parts[1], "message=")
+		} else {
+			httpErrStruct.Message = errMessage
+		}
 	}
 
 	// 구조화된 에러 로그 출력
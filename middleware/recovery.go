@@ -1 +1,88 @@
-package middleware
\ No newline at end of file
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/JokerTrickster/common/auth"
+	_error "github.com/JokerTrickster/common/error"
+	"github.com/JokerTrickster/common/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PanicAlertHook is called after a panic has been recovered and logged
+type PanicAlertHook func(c echo.Context, recovered interface{}, stack []byte)
+
+// RecoveryConfig defines the configuration for the recovery middleware
+type RecoveryConfig struct {
+	Logger    *logging.Logger
+	AlertHook PanicAlertHook // 슬랙 등 알림 연동 (optional)
+	StackSize int            // default 4KB
+}
+
+// RecoveryMiddleware recovers from panics with the default configuration
+func RecoveryMiddleware(logger *logging.Logger) echo.MiddlewareFunc {
+	return RecoveryWithConfig(RecoveryConfig{Logger: logger})
+}
+
+// RecoveryWithConfig recovers from panics, logs the stack and returns an INTERNAL_SERVER error
+func RecoveryWithConfig(config RecoveryConfig) echo.MiddlewareFunc {
+	if config.StackSize <= 0 {
+		config.StackSize = 4 << 10
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				stack := make([]byte, config.StackSize)
+				stack = stack[:runtime.Stack(stack, false)]
+
+				// 패닉 정보 로깅
+				if config.Logger != nil {
+					config.Logger.Error(logging.Log{
+						Url:       c.Request().URL.Path,
+						Method:    c.Request().Method,
+						RequestID: requestID(c),
+						UserID:    userID(c),
+						HttpCode:  _error.HttpCodeOf(string(_error.ErrInternalServer)),
+						ErrorInfo: fmt.Sprintf("panic: %v\n%s", r, stack),
+					})
+				}
+
+				if config.AlertHook != nil {
+					config.AlertHook(c, r, stack)
+				}
+
+				// 패닉 값은 로그와 Cause 에만 남기고 클라이언트에는 일반 메시지를 반환합니다.
+				err = _error.Wrap(c.Request().Context(), fmt.Errorf("panic: %v", r), string(_error.ErrInternalServer), _error.Trace(), "internal server error", string(_error.ErrFromInternal))
+			}()
+			return next(c)
+		}
+	}
+}
+
+// requestID returns the request ID from the response or request header
+func requestID(c echo.Context) string {
+	if rID := c.Response().Header().Get(echo.HeaderXRequestID); rID != "" {
+		return rID
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
+
+// userID returns the authenticated user ID set by jwt.TokenChecker
+func userID(c echo.Context) string {
+	if principal, ok := auth.FromEcho(c); ok {
+		return fmt.Sprintf("%d", principal.UserID)
+	}
+	return ""
+}
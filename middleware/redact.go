@@ -0,0 +1,53 @@
+package middleware
+
+import "sync"
+
+// redactedValue replaces sensitive query values in logs
+const redactedValue = "[REDACTED]"
+
+var (
+	redactMu sync.RWMutex
+	// 토큰, OAuth code/state 등 로그에 남기면 안 되는 쿼리 파라미터
+	redactedQueryParams = map[string]bool{
+		"token":         true,
+		"tkn":           true,
+		"access_token":  true,
+		"refresh_token": true,
+		"id_token":      true,
+		"code":          true,
+		"state":         true,
+	}
+)
+
+// RedactQueryParams adds query parameters whose values LoggingMiddleware must not log.
+// jwt.TokenCheckerWithConfig registers its "query:<name>" lookups automatically.
+func RedactQueryParams(names ...string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	for _, name := range names {
+		redactedQueryParams[name] = true
+	}
+}
+
+// redactQuery returns a copy of the query with sensitive values replaced
+func redactQuery(query map[string][]string) map[string][]string {
+	if len(query) == 0 {
+		return query
+	}
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+
+	redacted := make(map[string][]string, len(query))
+	for key, values := range query {
+		if !redactedQueryParams[key] {
+			redacted[key] = values
+			continue
+		}
+		masked := make([]string, len(values))
+		for i := range masked {
+			masked[i] = redactedValue
+		}
+		redacted[key] = masked
+	}
+	return redacted
+}
@@ -0,0 +1,283 @@
+package apple
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JokerTrickster/common/oauth"
+	"github.com/dgrijalva/jwt-go"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	issuer    = "https://appleid.apple.com"
+	keySetURL = "https://appleid.apple.com/auth/keys"
+	revokeURL = "https://appleid.apple.com/auth/revoke"
+
+	// clientSecretTTL must not exceed 6 months
+	clientSecretTTL = 24 * time.Hour
+)
+
+// Endpoint is Apple's OAuth 2.0 endpoint
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://appleid.apple.com/auth/authorize",
+	TokenURL:  "https://appleid.apple.com/auth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+// Config holds the Apple developer account settings
+type Config struct {
+	TeamID        string
+	KeyID         string // Sign in with Apple key ID
+	PrivateKeyPEM []byte // .p8 key downloaded from the developer portal
+	ServiceID     string // client_id used for the web code exchange
+	RedirectURL   string
+	ClientIDs     []string // accepted aud values (bundle IDs and service IDs)
+}
+
+type AppleService struct {
+	config     Config
+	privateKey *ecdsa.PrivateKey
+	initOnce   sync.Once
+}
+
+var appleInstance *AppleService
+var appleOnce sync.Once
+
+// GetAppleService returns the singleton instance of AppleService
+func GetAppleService() *AppleService {
+	appleOnce.Do(func() {
+		appleInstance = &AppleService{}
+	})
+	return appleInstance
+}
+
+// Initialize initializes the Apple Sign In configuration
+func (s *AppleService) Initialize(cfg Config) error {
+	var err error
+	s.initOnce.Do(func() {
+		if len(cfg.PrivateKeyPEM) > 0 {
+			key, parseErr := jwt.ParseECPrivateKeyFromPEM(cfg.PrivateKeyPEM)
+			if parseErr != nil {
+				err = fmt.Errorf("failed to parse Apple private key: %w", parseErr)
+				return
+			}
+			s.privateKey = key
+		}
+		if cfg.ServiceID != "" && !contains(cfg.ClientIDs, cfg.ServiceID) {
+			cfg.ClientIDs = append(cfg.ClientIDs, cfg.ServiceID)
+		}
+		s.config = cfg
+	})
+	return err
+}
+
+// Validate validates the Apple identity token and returns user data
+func (s *AppleService) Validate(ctx context.Context, token string) (oauth.OAuthData, error) {
+	return s.ValidateWithNonce(ctx, token, "")
+}
+
+// ValidateWithNonce validates the identity token from the apps and checks the nonce.
+// rawNonce is the value generated by the client; the apps send its SHA-256 hex digest to Apple.
+func (s *AppleService) ValidateWithNonce(ctx context.Context, token, rawNonce string) (oauth.OAuthData, error) {
+	var nonce string
+	if rawNonce != "" {
+		sum := sha256.Sum256([]byte(rawNonce))
+		nonce = hex.EncodeToString(sum[:])
+	}
+	return s.validate(ctx, token, nonce)
+}
+
+// ValidateIDToken validates the identity token of the web flow.
+// The nonce was sent to Apple as is and is required.
+func (s *AppleService) ValidateIDToken(ctx context.Context, token, nonce string) (oauth.OAuthData, error) {
+	if nonce == "" {
+		return oauth.OAuthData{}, fmt.Errorf("nonce is required")
+	}
+	return s.validate(ctx, token, nonce)
+}
+
+// validate verifies the identity token; the nonce claim must equal nonce when it is not empty
+func (s *AppleService) validate(ctx context.Context, token, nonce string) (oauth.OAuthData, error) {
+	if len(s.config.ClientIDs) == 0 {
+		return oauth.OAuthData{}, fmt.Errorf("Apple client IDs are not initialized")
+	}
+	claims, err := oauth.VerifyIDToken(ctx, token, keySetURL,
+		oauth.WithIssuers(issuer),
+		oauth.WithAudiences(s.config.ClientIDs...),
+	)
+	if err != nil {
+		return oauth.OAuthData{}, err
+	}
+
+	sub, okSub := claims["sub"].(string)
+	if !okSub || sub == "" {
+		return oauth.OAuthData{}, fmt.Errorf("invalid token claims: missing sub")
+	}
+
+	if nonce != "" {
+		tokenNonce, _ := claims["nonce"].(string)
+		if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+			return oauth.OAuthData{}, fmt.Errorf("invalid token nonce")
+		}
+	}
+
+	email, _ := claims["email"].(string)
+	return oauth.OAuthData{
+		ID:             sub,
+		Email:          email,
+		Provider:       "apple",
+		EmailVerified:  claimBool(claims["email_verified"]),
+		IsPrivateEmail: claimBool(claims["is_private_email"]),
+	}, nil
+}
+
+// ClientSecret generates the ES256 client secret JWT for the client ID
+func (s *AppleService) ClientSecret(clientID string) (string, error) {
+	if s.privateKey == nil {
+		return "", fmt.Errorf("Apple private key is not initialized")
+	}
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.StandardClaims{
+		Issuer:    s.config.TeamID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(clientSecretTTL).Unix(),
+		Audience:  issuer,
+		Subject:   clientID,
+	})
+	token.Header["kid"] = s.config.KeyID
+	return token.SignedString(s.privateKey)
+}
+
+// ExchangeToken exchanges an authorization code for tokens.
+// The identity token is available with token.Extra("id_token").
+func (s *AppleService) ExchangeToken(ctx context.Context, authCode string) (*oauth2.Token, error) {
+	return s.ExchangeTokenForClient(ctx, s.config.ServiceID, authCode)
+}
+
+// ExchangeTokenForClient exchanges a code issued to a specific client ID (ex. the iOS bundle ID)
+func (s *AppleService) ExchangeTokenForClient(ctx context.Context, clientID, authCode string) (*oauth2.Token, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("Apple OAuth configuration is not initialized")
+	}
+	secret, err := s.ClientSecret(clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: secret,
+		RedirectURL:  s.config.RedirectURL,
+		Endpoint:     Endpoint,
+	}
+	token, err := cfg.Exchange(ctx, authCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return token, nil
+}
+
+// claimBool reads Apple's boolean claims, which may be sent as "true"/"false" strings
+func claimBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		parsed, _ := strconv.ParseBool(b)
+		return parsed
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	_ oauth.Provider         = (*AppleService)(nil)
+	_ oauth.IDTokenValidator = (*AppleService)(nil)
+)
+
+// ExchangeCode exchanges an authorization code issued to the service ID
+func (s *AppleService) ExchangeCode(ctx context.Context, code string, opts ...oauth.AuthOption) (*oauth.Token, error) {
+	secret, err := s.ClientSecret(s.config.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	token, err := o.OAuth2Config(s.oauth2Config(secret)).Exchange(ctx, code, o.ExchangeOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// AuthURL returns the Apple login URL.
+// Apple posts the callback as a form when name or email scopes are requested.
+func (s *AppleService) AuthURL(opts ...oauth.AuthOption) (string, error) {
+	if s.config.ServiceID == "" {
+		return "", fmt.Errorf("Apple OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	cfg := o.OAuth2Config(s.oauth2Config(""))
+	params := o.AuthCodeOptions()
+	if len(cfg.Scopes) > 0 {
+		params = append(params, oauth2.SetAuthURLParam("response_mode", "form_post"))
+	}
+	return cfg.AuthCodeURL(o.State, params...), nil
+}
+
+// RefreshToken validates a refresh token and returns a new access token
+func (s *AppleService) RefreshToken(ctx context.Context, refreshToken string) (*oauth.Token, error) {
+	secret, err := s.ClientSecret(s.config.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	token, err := s.oauth2Config(secret).TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// Revoke revokes a refresh or access token (required when the user deletes the account)
+func (s *AppleService) Revoke(ctx context.Context, token string) error {
+	secret, err := s.ClientSecret(s.config.ServiceID)
+	if err != nil {
+		return err
+	}
+	_, err = oauth.PostForm(ctx, revokeURL, url.Values{
+		"client_id":     {s.config.ServiceID},
+		"client_secret": {secret},
+		"token":         {token},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// oauth2Config returns the service ID configuration with the client secret
+func (s *AppleService) oauth2Config(secret string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     s.config.ServiceID,
+		ClientSecret: secret,
+		RedirectURL:  s.config.RedirectURL,
+		Endpoint:     Endpoint,
+	}
+}
@@ -0,0 +1,99 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// FakeProvider is an in-memory Provider for tests.
+// Tokens map to users; codes exchange to tokens. Set Err before use, or call SetErr
+// while calls are running, to make every call fail.
+//
+//	fake := oauth.NewFakeProvider("google")
+//	fake.Users["token"] = oauth.OAuthData{ID: "1", Email: "a@b.com"}
+//	oauth.Register(oauth.AuthProviderGoogle, fake)
+type FakeProvider struct {
+	Name    string
+	Users   map[string]OAuthData // token -> user
+	Codes   map[string]*Token    // code -> token
+	Revoked []string
+	Err     error
+	mu      sync.Mutex
+}
+
+var _ Provider = (*FakeProvider)(nil)
+
+// NewFakeProvider creates an empty fake provider
+func NewFakeProvider(name string) *FakeProvider {
+	return &FakeProvider{Name: name, Users: map[string]OAuthData{}, Codes: map[string]*Token{}}
+}
+
+// SetErr sets the error returned by every call
+func (f *FakeProvider) SetErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Err = err
+}
+
+func (f *FakeProvider) Validate(ctx context.Context, token string) (OAuthData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return OAuthData{}, f.Err
+	}
+	user, ok := f.Users[token]
+	if !ok {
+		return OAuthData{}, fmt.Errorf("invalid token")
+	}
+	if user.Provider == "" {
+		user.Provider = f.Name
+	}
+	return user, nil
+}
+
+func (f *FakeProvider) ExchangeCode(ctx context.Context, code string, opts ...AuthOption) (*Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	token, ok := f.Codes[code]
+	if !ok {
+		return nil, fmt.Errorf("invalid code")
+	}
+	delete(f.Codes, code)
+	return token, nil
+}
+
+func (f *FakeProvider) AuthURL(opts ...AuthOption) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return "", f.Err
+	}
+	o := ApplyAuthOptions(opts...)
+	return "https://fake.example.com/authorize?" + url.Values{"state": {o.State}, "nonce": {o.Nonce}}.Encode(), nil
+}
+
+func (f *FakeProvider) RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return nil, f.Err
+	}
+	return &Token{AccessToken: "refreshed-" + refreshToken, RefreshToken: refreshToken, Expiry: time.Now().Add(time.Hour)}, nil
+}
+
+func (f *FakeProvider) Revoke(ctx context.Context, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.Revoked = append(f.Revoked, token)
+	delete(f.Users, token)
+	return nil
+}
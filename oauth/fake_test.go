@@ -0,0 +1,36 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestFakeProviderSetErrConcurrent(t *testing.T) {
+	ctx := context.Background()
+	fake := NewFakeProvider("google")
+	fake.Users["token"] = OAuthData{ID: "1", Email: "user@example.com"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = fake.Validate(ctx, "token")
+			_, _ = fake.AuthURL(WithState("state"))
+			_, _ = fake.RefreshToken(ctx, "refresh")
+			_, _ = fake.ExchangeCode(ctx, "code")
+		}()
+	}
+	providerErr := errors.New("provider unavailable")
+	fake.SetErr(providerErr)
+	wg.Wait()
+
+	if _, err := fake.AuthURL(); !errors.Is(err, providerErr) {
+		t.Errorf("AuthURL() error = %v, want %v", err, providerErr)
+	}
+	if _, err := fake.RefreshToken(ctx, "refresh"); !errors.Is(err, providerErr) {
+		t.Errorf("RefreshToken() error = %v, want %v", err, providerErr)
+	}
+}
@@ -2,7 +2,9 @@ package google
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"net/url"
 	"sync"
 
 	"github.com/JokerTrickster/common/oauth"
@@ -17,6 +19,13 @@ type GoogleService struct {
 	initOnce sync.Once
 }
 
+const (
+	keySetURL = "https://www.googleapis.com/oauth2/v3/certs"
+	revokeURL = "https://oauth2.googleapis.com/revoke"
+)
+
+var issuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
 var googleInstance *GoogleService
 var googleOnce sync.Once
 
@@ -33,16 +42,29 @@ func GetGoogleService() *GoogleService {
 	return googleInstance
 }
 
+// Option configures the OAuth settings in Initialize
+type Option func(cfg *oauth2.Config)
+
+// WithScopes sets the scopes requested by AuthURL; openid is always included for the id_token
+func WithScopes(scopes ...string) Option {
+	return func(cfg *oauth2.Config) {
+		cfg.Scopes = withOpenID(scopes)
+	}
+}
+
 // Initialize initializes the Google OAuth configuration
-func (s *GoogleService) Initialize(clientID, clientSecret, redirectURL string, googleIosID string, googleAndIDs []string) {
+func (s *GoogleService) Initialize(clientID, clientSecret, redirectURL string, googleIosID string, googleAndIDs []string, opts ...Option) {
 	s.initOnce.Do(func() {
 		s.config = &oauth2.Config{
 			ClientID:     clientID,
 			ClientSecret: clientSecret,
 			RedirectURL:  redirectURL,
-			Scopes:       []string{"https://www.googleapis.com/auth/userinfo.email"},
+			Scopes:       withOpenID([]string{"https://www.googleapis.com/auth/userinfo.email"}),
 			Endpoint:     google.Endpoint,
 		}
+		for _, opt := range opts {
+			opt(s.config)
+		}
 		s.authMeta = AuthMeta{
 			GoogleIosID: googleIosID,
 			GoogleAndID: googleAndIDs,
@@ -50,42 +72,82 @@ func (s *GoogleService) Initialize(clientID, clientSecret, redirectURL string, g
 	})
 }
 
-// Validate validates the Google token and returns user data
+// Validate validates the Google ID token from the mobile apps and returns user data
 func (s *GoogleService) Validate(ctx context.Context, token string) (oauth.OAuthData, error) {
-	claims, err := oauth.JwtVerifyWithKeySet(ctx, "google", token, "https://www.googleapis.com/oauth2/v3/certs")
+	return s.ValidateIDToken(ctx, token, "")
+}
+
+// ValidateIDToken validates a Google ID token; nonce is compared when it is not empty
+func (s *GoogleService) ValidateIDToken(ctx context.Context, token, nonce string) (oauth.OAuthData, error) {
+	audiences := s.audiences()
+	if len(audiences) == 0 {
+		return oauth.OAuthData{}, fmt.Errorf("Google client IDs are not initialized")
+	}
+	claims, err := oauth.VerifyIDToken(ctx, token, keySetURL,
+		oauth.WithIssuers(issuers...),
+		oauth.WithAudiences(audiences...),
+	)
 	if err != nil {
-		fmt.Println("왜 에러가 나지?")
-		fmt.Println(err)
 		return oauth.OAuthData{}, err
 	}
-	fmt.Println(claims)
-	fmt.Println(s.authMeta.GoogleIosID)
-	aud, okAud := claims["aud"].(string)
-	iss, okIss := claims["iss"].(string)
+
 	sub, okSub := claims["sub"].(string)
 	email, okEmail := claims["email"].(string)
-
-	if !okAud || !okIss || !okSub || !okEmail ||
-		(aud != s.authMeta.GoogleIosID && s.isGoogleIDNotExisted(aud)) ||
-		(iss != "accounts.google.com" && iss != "https://accounts.google.com") {
-		fmt.Println("여기 들어오나??")
-		return oauth.OAuthData{}, fmt.Errorf("invalid token claims: %+v", claims)
+	if !okSub || !okEmail || sub == "" {
+		return oauth.OAuthData{}, fmt.Errorf("invalid token claims: missing sub or email")
+	}
+	if nonce != "" {
+		tokenNonce, _ := claims["nonce"].(string)
+		if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+			return oauth.OAuthData{}, fmt.Errorf("invalid token nonce")
+		}
 	}
-	fmt.Println(email)
+
+	emailVerified, _ := claims["email_verified"].(bool)
+	name, _ := claims["name"].(string)
+	picture, _ := claims["picture"].(string)
 	return oauth.OAuthData{
-		ID:       sub,
-		Email:    email,
-		Provider: "google",
+		ID:            sub,
+		Email:         email,
+		Provider:      "google",
+		Name:          name,
+		Image:         picture,
+		EmailVerified: emailVerified,
 	}, nil
 }
 
-func (s *GoogleService) isGoogleIDNotExisted(key string) bool {
-	for _, id := range s.authMeta.GoogleAndID {
-		if key == id {
-			return false
+// BeginLogin stores state and PKCE and returns the Google login URL
+func (s *GoogleService) BeginLogin(ctx context.Context, flow *oauth.WebFlow, returnTo string, opts ...oauth.AuthOption) (string, error) {
+	return flow.BeginWith(ctx, oauth.AuthProviderGoogle, s, returnTo, opts...)
+}
+
+// CompleteLogin verifies the callback state, exchanges the code and verifies the id_token
+//
+//	user, state, err := google.GetGoogleService().CompleteLogin(ctx, flow, c.QueryParam("state"), c.QueryParam("code"))
+func (s *GoogleService) CompleteLogin(ctx context.Context, flow *oauth.WebFlow, state, code string) (oauth.OAuthData, *oauth.StateData, error) {
+	return flow.CallbackWith(ctx, oauth.AuthProviderGoogle, s, state, code)
+}
+
+// audiences returns the accepted aud values (iOS, Android and web client IDs)
+func (s *GoogleService) audiences() []string {
+	audiences := append([]string{}, s.authMeta.GoogleAndID...)
+	if s.authMeta.GoogleIosID != "" {
+		audiences = append(audiences, s.authMeta.GoogleIosID)
+	}
+	if s.config != nil && s.config.ClientID != "" {
+		audiences = append(audiences, s.config.ClientID)
+	}
+	return audiences
+}
+
+// withOpenID adds the openid scope when it is missing
+func withOpenID(scopes []string) []string {
+	for _, scope := range scopes {
+		if scope == "openid" {
+			return scopes
 		}
 	}
-	return true
+	return append([]string{"openid"}, scopes...)
 }
 
 // ExchangeToken exchanges an authorization code for an access token
@@ -103,3 +165,47 @@ func (s *GoogleService) ExchangeToken(ctx context.Context, authCode string) (*oa
 
 	return token, nil
 }
+
+var _ oauth.Provider = (*GoogleService)(nil)
+
+// ExchangeCode exchanges an authorization code for tokens
+func (s *GoogleService) ExchangeCode(ctx context.Context, code string, opts ...oauth.AuthOption) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Google OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	token, err := o.OAuth2Config(s.config).Exchange(ctx, code, o.ExchangeOptions()...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// AuthURL returns the Google login URL
+func (s *GoogleService) AuthURL(opts ...oauth.AuthOption) (string, error) {
+	if s.config == nil {
+		return "", fmt.Errorf("Google OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	return o.OAuth2Config(s.config).AuthCodeURL(o.State, o.AuthCodeOptions()...), nil
+}
+
+// RefreshToken gets a new access token with a refresh token
+func (s *GoogleService) RefreshToken(ctx context.Context, refreshToken string) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Google OAuth configuration is not initialized")
+	}
+	token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// Revoke revokes an access or refresh token
+func (s *GoogleService) Revoke(ctx context.Context, token string) error {
+	if _, err := oauth.PostForm(ctx, revokeURL, url.Values{"token": {token}}, nil); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
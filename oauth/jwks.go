@@ -0,0 +1,266 @@
+package oauth
+
+/*
+	JWKS 캐시 및 ID 토큰 검증
+	- URL 별로 키셋을 캐시하고 Cache-Control/Expires 에 맞춰 백그라운드에서 갱신합니다.
+	- 모르는 kid 가 오면 키 교체로 보고 한 번 다시 가져옵니다 (최소 간격 제한).
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+var (
+	ErrJWKSFetch         = errors.New("failed to fetch JWKS")
+	ErrKeyNotFound       = errors.New("signing key not found in JWKS")
+	ErrInvalidSigningAlg = errors.New("unexpected token signing method")
+	ErrInvalidToken      = errors.New("invalid token")
+	ErrTokenExpired      = errors.New("token expired")
+	ErrTokenNotYetValid  = errors.New("token not yet valid")
+	ErrInvalidIssuer     = errors.New("invalid token issuer")
+	ErrInvalidAudience   = errors.New("invalid token audience")
+)
+
+// defaultMinRefetchWait is the minimum time between refetches for unknown kids
+const defaultMinRefetchWait = 5 * time.Minute
+
+// KeySetCache caches JWKS per URL
+type KeySetCache struct {
+	ar             *jwk.AutoRefresh
+	mu             sync.Mutex
+	lastRefetch    map[string]time.Time
+	minRefetchWait time.Duration
+}
+
+var (
+	keySetCache     *KeySetCache
+	keySetCacheOnce sync.Once
+)
+
+// GetKeySetCache returns the shared cache used by JwtVerifyWithKeySet
+func GetKeySetCache() *KeySetCache {
+	keySetCacheOnce.Do(func() {
+		keySetCache = NewKeySetCache(context.Background(), defaultMinRefetchWait)
+	})
+	return keySetCache
+}
+
+// NewKeySetCache creates a cache whose background refresh stops when ctx is done.
+// minRefetchWait limits refetches triggered by unknown kids.
+func NewKeySetCache(ctx context.Context, minRefetchWait time.Duration) *KeySetCache {
+	return &KeySetCache{
+		ar:             jwk.NewAutoRefresh(ctx),
+		lastRefetch:    map[string]time.Time{},
+		minRefetchWait: minRefetchWait,
+	}
+}
+
+// LookupKey returns the public key for the kid, refetching once when it is unknown
+func (c *KeySetCache) LookupKey(ctx context.Context, keySetUrl, kid string) (jwk.Key, error) {
+	if !c.ar.IsRegistered(keySetUrl) {
+		c.ar.Configure(keySetUrl, jwk.WithHTTPClient(httpClient), jwk.WithMinRefreshInterval(15*time.Minute))
+	}
+
+	ctxHttp, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	set, err := c.ar.Fetch(ctxHttp, keySetUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSFetch, err)
+	}
+	if key, ok := set.LookupKeyID(kid); ok {
+		return key, nil
+	}
+
+	if !c.allowRefetch(keySetUrl) {
+		return nil, fmt.Errorf("%w: kid %s", ErrKeyNotFound, kid)
+	}
+	set, err = c.ar.Refresh(ctxHttp, keySetUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSFetch, err)
+	}
+	if key, ok := set.LookupKeyID(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: kid %s", ErrKeyNotFound, kid)
+}
+
+// allowRefetch rate-limits refetches so random kids cannot hammer the provider
+func (c *KeySetCache) allowRefetch(keySetUrl string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if last, ok := c.lastRefetch[keySetUrl]; ok && time.Since(last) < c.minRefetchWait {
+		return false
+	}
+	c.lastRefetch[keySetUrl] = time.Now()
+	return true
+}
+
+// VerifyOptions are the claim checks of VerifyIDToken
+type VerifyOptions struct {
+	Issuers   []string // any of; empty skips the check
+	Audiences []string // any of; empty skips the check
+	Leeway    time.Duration
+	Cache     *KeySetCache
+}
+
+// VerifyOption sets a VerifyOptions field
+type VerifyOption func(*VerifyOptions)
+
+// WithIssuers requires the iss claim to be one of the issuers
+func WithIssuers(issuers ...string) VerifyOption {
+	return func(o *VerifyOptions) { o.Issuers = issuers }
+}
+
+// WithAudiences requires the aud claim to contain one of the audiences
+func WithAudiences(audiences ...string) VerifyOption {
+	return func(o *VerifyOptions) { o.Audiences = audiences }
+}
+
+// WithLeeway allows clock skew for exp/iat/nbf
+func WithLeeway(leeway time.Duration) VerifyOption {
+	return func(o *VerifyOptions) { o.Leeway = leeway }
+}
+
+// WithKeySetCache uses a cache other than GetKeySetCache
+func WithKeySetCache(cache *KeySetCache) VerifyOption {
+	return func(o *VerifyOptions) { o.Cache = cache }
+}
+
+// VerifyIDToken verifies an RSA or EC signed token against the JWKS and validates exp/iat/nbf/iss/aud
+func VerifyIDToken(ctx context.Context, tokenString string, keySetUrl string, opts ...VerifyOption) (jwt.MapClaims, error) {
+	o := VerifyOptions{Leeway: time.Minute}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Cache == nil {
+		o.Cache = GetKeySetCache()
+	}
+
+	claims := jwt.MapClaims{}
+	parser := &jwt.Parser{SkipClaimsValidation: true}
+	_, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSigningAlg, token.Header["alg"])
+		}
+
+		keyID, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: missing key ID (kid) in token header", ErrInvalidToken)
+		}
+		key, err := o.Cache.LookupKey(ctx, keySetUrl, keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		var pubKey interface{}
+		if err := key.Raw(&pubKey); err != nil {
+			return nil, fmt.Errorf("failed to get raw key: %w", err)
+		}
+		return pubKey, nil
+	})
+	if err != nil {
+		var vErr *jwt.ValidationError
+		if errors.As(err, &vErr) && vErr.Inner != nil {
+			if isTypedError(vErr.Inner) {
+				return nil, vErr.Inner
+			}
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, vErr.Inner)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if err := validateClaims(claims, o); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// JwtVerifyWithKeySet verifies the token signature with the cached JWKS and validates exp/iat/nbf.
+// Callers check iss and aud; use VerifyIDToken to have them checked here.
+func JwtVerifyWithKeySet(ctx context.Context, p string, tokenString string, keySetUrl string) (jwt.MapClaims, error) {
+	return VerifyIDToken(ctx, tokenString, keySetUrl)
+}
+
+// validateClaims checks the time, issuer and audience claims
+func validateClaims(claims jwt.MapClaims, o VerifyOptions) error {
+	now := time.Now()
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(exp, 0).Add(o.Leeway)) {
+		return ErrTokenExpired
+	}
+	if iat, ok := numericClaim(claims, "iat"); ok && time.Unix(iat, 0).After(now.Add(o.Leeway)) {
+		return fmt.Errorf("%w: issued in the future", ErrTokenNotYetValid)
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && time.Unix(nbf, 0).After(now.Add(o.Leeway)) {
+		return ErrTokenNotYetValid
+	}
+
+	if len(o.Issuers) > 0 {
+		iss, _ := claims["iss"].(string)
+		if !containsString(o.Issuers, iss) {
+			return fmt.Errorf("%w: %s", ErrInvalidIssuer, iss)
+		}
+	}
+	if len(o.Audiences) > 0 && !audienceMatches(claims["aud"], o.Audiences) {
+		return fmt.Errorf("%w: %v", ErrInvalidAudience, claims["aud"])
+	}
+	return nil
+}
+
+// numericClaim reads a NumericDate claim
+func numericClaim(claims jwt.MapClaims, name string) (int64, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
+}
+
+// audienceMatches reports whether aud (string or array) contains one of the audiences
+func audienceMatches(aud interface{}, audiences []string) bool {
+	switch v := aud.(type) {
+	case string:
+		return containsString(audiences, v)
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok && containsString(audiences, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// isTypedError reports whether err already wraps one of the package errors
+func isTypedError(err error) bool {
+	for _, target := range []error{ErrJWKSFetch, ErrKeyNotFound, ErrInvalidSigningAlg, ErrInvalidToken} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
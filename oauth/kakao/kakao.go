@@ -2,20 +2,42 @@ package kakao
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"sync"
 	"time"
 
 	"github.com/JokerTrickster/common/oauth"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	issuer         = "https://kauth.kakao.com"
+	keySetURL      = "https://kauth.kakao.com/.well-known/jwks.json"
+	tokenInfoURL   = "https://kapi.kakao.com/v1/user/access_token_info"
+	userMeURL      = "https://kapi.kakao.com/v2/user/me"
+	unlinkURL      = "https://kapi.kakao.com/v1/user/unlink"
+	defaultTimeout = 10 * time.Second
 )
 
+// Endpoint is Kakao's OAuth 2.0 endpoint
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://kauth.kakao.com/oauth/authorize",
+	TokenURL:  "https://kauth.kakao.com/oauth/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
 type KakaoService struct {
-	appID    int64
-	initOnce sync.Once
+	appID     int64
+	config    *oauth2.Config
+	initOnce  sync.Once
+	oauthOnce sync.Once
 }
 
 var kakaoInstance *KakaoService
@@ -43,47 +65,176 @@ func (s *KakaoService) Initialize(appID string) error {
 	return err
 }
 
-// Validate validates the Kakao token and returns user data
+// InitializeOAuth configures the code exchange and OIDC verification.
+// clientID is the REST API key; clientSecret is optional unless enabled in the console.
+func (s *KakaoService) InitializeOAuth(clientID, clientSecret, redirectURL string) {
+	s.oauthOnce.Do(func() {
+		s.config = &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     Endpoint,
+		}
+	})
+}
+
+// Validate validates the Kakao access token and returns the user with email and profile
 func (s *KakaoService) Validate(ctx context.Context, token string) (oauth.OAuthData, error) {
-	url := "https://kapi.kakao.com/v1/user/access_token_info"
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	var info struct {
+		AppID int64 `json:"app_id"`
+		ID    int64 `json:"id"`
+	}
+	if err := s.getJSON(ctx, tokenInfoURL, token, &info); err != nil {
+		return oauth.OAuthData{}, err
+	}
+	if info.AppID != s.appID {
+		return oauth.OAuthData{}, fmt.Errorf("invalid app ID: %d", info.AppID)
+	}
+
+	var me struct {
+		ID           int64 `json:"id"`
+		KakaoAccount struct {
+			Email           string `json:"email"`
+			IsEmailValid    bool   `json:"is_email_valid"`
+			IsEmailVerified bool   `json:"is_email_verified"`
+			Profile         struct {
+				Nickname        string `json:"nickname"`
+				ProfileImageURL string `json:"profile_image_url"`
+			} `json:"profile"`
+		} `json:"kakao_account"`
+	}
+	if err := s.getJSON(ctx, userMeURL, token, &me); err != nil {
+		return oauth.OAuthData{}, err
+	}
+	if me.ID != info.ID {
+		return oauth.OAuthData{}, fmt.Errorf("user ID mismatch: %d != %d", me.ID, info.ID)
+	}
+
+	account := me.KakaoAccount
+	return oauth.OAuthData{
+		ID:            strconv.FormatInt(info.ID, 10),
+		Email:         account.Email,
+		Provider:      "kakao",
+		Name:          account.Profile.Nickname,
+		Image:         account.Profile.ProfileImageURL,
+		EmailVerified: account.Email != "" && account.IsEmailValid && account.IsEmailVerified,
+	}, nil
+}
+
+// ValidateIDToken verifies an OpenID Connect id_token with Kakao's JWKS.
+// nonce is compared when it is not empty.
+func (s *KakaoService) ValidateIDToken(ctx context.Context, idToken, nonce string) (oauth.OAuthData, error) {
+	if s.config == nil {
+		return oauth.OAuthData{}, fmt.Errorf("Kakao OAuth configuration is not initialized")
+	}
+	claims, err := oauth.VerifyIDToken(ctx, idToken, keySetURL,
+		oauth.WithIssuers(issuer),
+		oauth.WithAudiences(s.config.ClientID),
+	)
 	if err != nil {
-		return oauth.OAuthData{}, fmt.Errorf("failed to create request: %w", err)
+		return oauth.OAuthData{}, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token)
-	client := &http.Client{Timeout: 10 * time.Second}
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return oauth.OAuthData{}, fmt.Errorf("invalid token claims: missing sub")
+	}
+	if nonce != "" {
+		tokenNonce, _ := claims["nonce"].(string)
+		if subtle.ConstantTimeCompare([]byte(tokenNonce), []byte(nonce)) != 1 {
+			return oauth.OAuthData{}, fmt.Errorf("invalid token nonce")
+		}
+	}
 
-	resp, err := client.Do(req)
+	email, _ := claims["email"].(string)
+	nickname, _ := claims["nickname"].(string)
+	picture, _ := claims["picture"].(string)
+	return oauth.OAuthData{
+		ID:       sub,
+		Email:    email,
+		Provider: "kakao",
+		Name:     nickname,
+		Image:    picture,
+		// id_token 의 email 은 카카오에서 인증된 이메일만 포함됩니다.
+		EmailVerified: email != "",
+	}, nil
+}
+
+var _ oauth.Provider = (*KakaoService)(nil)
+
+// ExchangeCode exchanges an authorization code for tokens.
+// The id_token is included when the openid scope was requested.
+func (s *KakaoService) ExchangeCode(ctx context.Context, code string, opts ...oauth.AuthOption) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Kakao OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	token, err := o.OAuth2Config(s.config).Exchange(ctx, code, o.ExchangeOptions()...)
 	if err != nil {
-		return oauth.OAuthData{}, fmt.Errorf("failed to send request: %w", err)
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
 	}
-	defer resp.Body.Close()
+	return oauth.TokenFromOAuth2(token), nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return oauth.OAuthData{}, fmt.Errorf("invalid token: %s", resp.Status)
+// AuthURL returns the Kakao login URL
+func (s *KakaoService) AuthURL(opts ...oauth.AuthOption) (string, error) {
+	if s.config == nil {
+		return "", fmt.Errorf("Kakao OAuth configuration is not initialized")
 	}
+	o := oauth.ApplyAuthOptions(opts...)
+	return o.OAuth2Config(s.config).AuthCodeURL(o.State, o.AuthCodeOptions()...), nil
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+// RefreshToken gets a new access token; Kakao returns a new refresh token only near its expiry
+func (s *KakaoService) RefreshToken(ctx context.Context, refreshToken string) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Kakao OAuth configuration is not initialized")
+	}
+	token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
 	if err != nil {
-		return oauth.OAuthData{}, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
 	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
 
-	var data struct {
-		AppID int64 `json:"app_id"`
-		ID    int64 `json:"id"`
+// Revoke unlinks the app from the user's Kakao account with the user's access token
+func (s *KakaoService) Revoke(ctx context.Context, token string) error {
+	_, err := oauth.PostForm(ctx, unlinkURL, url.Values{}, http.Header{"Authorization": {"Bearer " + token}})
+	if err != nil {
+		return fmt.Errorf("failed to unlink user: %w", err)
+	}
+	return nil
+}
+
+// getJSON calls a Kakao API with the access token and decodes the response
+func (s *KakaoService) getJSON(ctx context.Context, url, token string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if err := json.Unmarshal(body, &data); err != nil {
-		return oauth.OAuthData{}, fmt.Errorf("failed to parse response: %w", err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: defaultTimeout}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
 	}
+	defer resp.Body.Close()
 
-	if data.AppID != s.appID {
-		return oauth.OAuthData{}, fmt.Errorf("invalid app ID: %d", data.AppID)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("invalid token: %s", resp.Status)
 	}
 
-	return oauth.OAuthData{
-		ID:       strconv.FormatInt(data.ID, 10),
-		Provider: "kakao",
-	}, nil
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,188 @@
+package naver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/JokerTrickster/common/oauth"
+
+	"golang.org/x/oauth2"
+)
+
+const profileURL = "https://openapi.naver.com/v1/nid/me"
+
+// Endpoint is Naver's OAuth 2.0 endpoint
+var Endpoint = oauth2.Endpoint{
+	AuthURL:   "https://nid.naver.com/oauth2.0/authorize",
+	TokenURL:  "https://nid.naver.com/oauth2.0/token",
+	AuthStyle: oauth2.AuthStyleInParams,
+}
+
+type NaverService struct {
+	config   *oauth2.Config
+	initOnce sync.Once
+}
+
+var naverInstance *NaverService
+var naverOnce sync.Once
+
+// GetNaverService returns the singleton instance of NaverService
+func GetNaverService() *NaverService {
+	naverOnce.Do(func() {
+		naverInstance = &NaverService{}
+	})
+	return naverInstance
+}
+
+// Initialize initializes the Naver OAuth configuration
+func (s *NaverService) Initialize(clientID, clientSecret, redirectURL string) {
+	s.initOnce.Do(func() {
+		s.config = &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     Endpoint,
+		}
+	})
+}
+
+// Validate validates the Naver access token with the profile API and returns user data
+func (s *NaverService) Validate(ctx context.Context, token string) (oauth.OAuthData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, profileURL, nil)
+	if err != nil {
+		return oauth.OAuthData{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return oauth.OAuthData{}, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return oauth.OAuthData{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var data struct {
+		ResultCode string `json:"resultcode"`
+		Message    string `json:"message"`
+		Response   struct {
+			ID    string `json:"id"`
+			Email string `json:"email"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return oauth.OAuthData{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// 네이버는 실패 시 resultcode 가 "00" 이 아닌 값을 반환합니다.
+	if resp.StatusCode != http.StatusOK || data.ResultCode != "00" {
+		return oauth.OAuthData{}, fmt.Errorf("invalid token: %s %s", resp.Status, data.Message)
+	}
+	if data.Response.ID == "" {
+		return oauth.OAuthData{}, fmt.Errorf("invalid profile response: missing id")
+	}
+
+	return oauth.OAuthData{
+		ID:       data.Response.ID,
+		Email:    data.Response.Email,
+		Provider: "naver",
+	}, nil
+}
+
+// AuthCodeURL returns the Naver login URL; state must be checked in the callback
+func (s *NaverService) AuthCodeURL(state string) (string, error) {
+	if s.config == nil {
+		return "", fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+	return s.config.AuthCodeURL(state), nil
+}
+
+// ExchangeToken exchanges an authorization code for an access token.
+// Naver requires the state used in the authorization request.
+func (s *NaverService) ExchangeToken(ctx context.Context, authCode, state string) (*oauth2.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+
+	token, err := s.config.Exchange(ctx, authCode, oauth2.SetAuthURLParam("state", state))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return token, nil
+}
+
+var _ oauth.Provider = (*NaverService)(nil)
+
+// ExchangeCode exchanges an authorization code for tokens (WithState is required by Naver)
+func (s *NaverService) ExchangeCode(ctx context.Context, code string, opts ...oauth.AuthOption) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	params := append(o.ExchangeOptions(), oauth2.SetAuthURLParam("state", o.State))
+	token, err := o.OAuth2Config(s.config).Exchange(ctx, code, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// AuthURL returns the Naver login URL
+func (s *NaverService) AuthURL(opts ...oauth.AuthOption) (string, error) {
+	if s.config == nil {
+		return "", fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+	o := oauth.ApplyAuthOptions(opts...)
+	return o.OAuth2Config(s.config).AuthCodeURL(o.State, o.AuthCodeOptions()...), nil
+}
+
+// RefreshToken gets a new access token with a refresh token
+func (s *NaverService) RefreshToken(ctx context.Context, refreshToken string) (*oauth.Token, error) {
+	if s.config == nil {
+		return nil, fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+	token, err := s.config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	return oauth.TokenFromOAuth2(token), nil
+}
+
+// Revoke deletes the access token, which unlinks the app from the Naver account
+func (s *NaverService) Revoke(ctx context.Context, token string) error {
+	if s.config == nil {
+		return fmt.Errorf("Naver OAuth configuration is not initialized")
+	}
+	body, err := oauth.PostForm(ctx, Endpoint.TokenURL, url.Values{
+		"grant_type":       {"delete"},
+		"client_id":        {s.config.ClientID},
+		"client_secret":    {s.config.ClientSecret},
+		"access_token":     {token},
+		"service_provider": {"NAVER"},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	var data struct {
+		Result string `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	if data.Result != "success" {
+		return fmt.Errorf("failed to revoke token: %s", data.Error)
+	}
+	return nil
+}
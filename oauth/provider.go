@@ -0,0 +1,228 @@
+package oauth
+
+/*
+	OAuth 공통 Provider 인터페이스 및 레지스트리
+	- 로그인 핸들러는 provider 이름으로 Provider 를 찾아 처리합니다.
+*/
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrNotSupported is returned when a provider does not support an operation
+var ErrNotSupported = errors.New("operation not supported by provider")
+
+// ErrUnknownProvider is returned for provider names or values that are not registered
+var ErrUnknownProvider = errors.New("unknown auth provider")
+
+// Token is the provider token returned by ExchangeCode and RefreshToken
+type Token struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	IDToken      string    `json:"idToken,omitempty"`
+	TokenType    string    `json:"tokenType,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+}
+
+// Provider is implemented by every OAuth service (google, kakao, naver, apple)
+type Provider interface {
+	// Validate verifies an access or ID token from the client and returns the user
+	Validate(ctx context.Context, token string) (OAuthData, error)
+	// ExchangeCode exchanges an authorization code for tokens
+	ExchangeCode(ctx context.Context, code string, opts ...AuthOption) (*Token, error)
+	// AuthURL returns the authorization URL for the web login flow
+	AuthURL(opts ...AuthOption) (string, error)
+	// RefreshToken gets a new token with a refresh token
+	RefreshToken(ctx context.Context, refreshToken string) (*Token, error)
+	// Revoke revokes the token or unlinks the app from the user account
+	Revoke(ctx context.Context, token string) error
+}
+
+// AuthOptions are the optional parameters of the authorization code flow
+type AuthOptions struct {
+	State        string
+	Nonce        string
+	CodeVerifier string // PKCE; the S256 challenge is derived from it
+	RedirectURL  string
+	Scopes       []string
+}
+
+// AuthOption sets an AuthOptions field
+type AuthOption func(*AuthOptions)
+
+// WithState sets the state parameter
+func WithState(state string) AuthOption {
+	return func(o *AuthOptions) { o.State = state }
+}
+
+// WithNonce sets the OIDC nonce parameter
+func WithNonce(nonce string) AuthOption {
+	return func(o *AuthOptions) { o.Nonce = nonce }
+}
+
+// WithPKCE sets the PKCE code verifier
+func WithPKCE(codeVerifier string) AuthOption {
+	return func(o *AuthOptions) { o.CodeVerifier = codeVerifier }
+}
+
+// WithRedirectURL overrides the configured redirect URL
+func WithRedirectURL(redirectURL string) AuthOption {
+	return func(o *AuthOptions) { o.RedirectURL = redirectURL }
+}
+
+// WithScopes overrides the configured scopes
+func WithScopes(scopes ...string) AuthOption {
+	return func(o *AuthOptions) { o.Scopes = scopes }
+}
+
+// ApplyAuthOptions collects the options
+func ApplyAuthOptions(opts ...AuthOption) AuthOptions {
+	var o AuthOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// OAuth2Config returns a copy of cfg with the redirect URL and scopes overridden
+func (o AuthOptions) OAuth2Config(cfg *oauth2.Config) *oauth2.Config {
+	c := *cfg
+	if o.RedirectURL != "" {
+		c.RedirectURL = o.RedirectURL
+	}
+	if len(o.Scopes) > 0 {
+		c.Scopes = o.Scopes
+	}
+	return &c
+}
+
+// AuthCodeOptions returns the oauth2 parameters for AuthCodeURL
+func (o AuthOptions) AuthCodeOptions() []oauth2.AuthCodeOption {
+	var params []oauth2.AuthCodeOption
+	if o.Nonce != "" {
+		params = append(params, oauth2.SetAuthURLParam("nonce", o.Nonce))
+	}
+	if o.CodeVerifier != "" {
+		params = append(params, oauth2.S256ChallengeOption(o.CodeVerifier))
+	}
+	return params
+}
+
+// ExchangeOptions returns the oauth2 parameters for Exchange
+func (o AuthOptions) ExchangeOptions() []oauth2.AuthCodeOption {
+	var params []oauth2.AuthCodeOption
+	if o.CodeVerifier != "" {
+		params = append(params, oauth2.VerifierOption(o.CodeVerifier))
+	}
+	return params
+}
+
+// TokenFromOAuth2 converts an oauth2 token, including the id_token extra
+func TokenFromOAuth2(token *oauth2.Token) *Token {
+	if token == nil {
+		return nil
+	}
+	idToken, _ := token.Extra("id_token").(string)
+	return &Token{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		IDToken:      idToken,
+		TokenType:    token.TokenType,
+		Expiry:       token.Expiry,
+	}
+}
+
+// PostForm sends a form POST and returns the body, failing on non-2xx responses
+func PostForm(ctx context.Context, endpoint string, values url.Values, header http.Header) ([]byte, error) {
+	ctxHttp, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctxHttp, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for key, vals := range header {
+		for _, v := range vals {
+			req.Header.Add(key, v)
+		}
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("unexpected status %s: %s", res.Status, body)
+	}
+	return body, nil
+}
+
+// String returns the provider name (ex. "google")
+func (p AuthProvider) String() string {
+	if name, ok := authProviderName[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("AuthProvider(%d)", uint8(p))
+}
+
+// ParseAuthProvider converts a provider name into an AuthProvider
+func ParseAuthProvider(name string) (AuthProvider, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for p, n := range authProviderName {
+		if n == name {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[AuthProvider]Provider{}
+)
+
+// Register registers the provider implementation, replacing any previous one
+//
+//	oauth.Register(oauth.AuthProviderGoogle, google.GetGoogleService())
+func Register(p AuthProvider, provider Provider) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[p] = provider
+}
+
+// Get returns the registered provider
+func Get(p AuthProvider) (Provider, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	provider, ok := registry[p]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvider, p)
+	}
+	return provider, nil
+}
+
+// GetByName returns the registered provider for a name such as "kakao"
+func GetByName(name string) (Provider, error) {
+	p, err := ParseAuthProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	return Get(p)
+}
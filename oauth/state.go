@@ -0,0 +1,148 @@
+package oauth
+
+/*
+	웹 로그인 (Authorization Code + PKCE) 흐름
+	- state, PKCE verifier, nonce 를 저장소에 저장하고 콜백에서 한 번만 꺼내 검증합니다.
+*/
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// ErrInvalidState is returned when the callback state is unknown, expired or already used
+var ErrInvalidState = errors.New("invalid oauth state")
+
+// StateData is stored per login attempt
+type StateData struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"codeVerifier"`
+	Nonce        string `json:"nonce"`
+	RedirectURL  string `json:"redirectURL,omitempty"`
+	ReturnTo     string `json:"returnTo,omitempty"` // where the app sends the user after login
+}
+
+// StateStore stores StateData until the callback (RedisStateStore)
+type StateStore interface {
+	Save(ctx context.Context, state string, data StateData, ttl time.Duration) error
+	// Consume returns and deletes the data; ErrInvalidState when it does not exist
+	Consume(ctx context.Context, state string) (*StateData, error)
+}
+
+// IDTokenValidator is implemented by providers that can verify an OIDC id_token with a nonce
+type IDTokenValidator interface {
+	ValidateIDToken(ctx context.Context, idToken, nonce string) (OAuthData, error)
+}
+
+// WebFlow runs the authorization code flow for registered providers
+type WebFlow struct {
+	store StateStore
+	ttl   time.Duration
+}
+
+// NewWebFlow creates a web flow; ttl is how long a login attempt may take (default 10m)
+func NewWebFlow(store StateStore, ttl time.Duration) *WebFlow {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return &WebFlow{store: store, ttl: ttl}
+}
+
+// Begin stores a new state and returns the login URL of the registered provider
+func (f *WebFlow) Begin(ctx context.Context, p AuthProvider, returnTo string, opts ...AuthOption) (string, error) {
+	provider, err := Get(p)
+	if err != nil {
+		return "", err
+	}
+	return f.BeginWith(ctx, p, provider, returnTo, opts...)
+}
+
+// BeginWith is Begin with an explicit provider implementation
+func (f *WebFlow) BeginWith(ctx context.Context, p AuthProvider, provider Provider, returnTo string, opts ...AuthOption) (string, error) {
+	state, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString(32)
+	if err != nil {
+		return "", err
+	}
+	o := ApplyAuthOptions(opts...)
+	data := StateData{
+		Provider:     p.String(),
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		RedirectURL:  o.RedirectURL,
+		ReturnTo:     returnTo,
+	}
+	if err := f.store.Save(ctx, state, data, f.ttl); err != nil {
+		return "", fmt.Errorf("failed to save oauth state: %w", err)
+	}
+
+	opts = append(append([]AuthOption{}, opts...), WithState(state), WithNonce(nonce), WithPKCE(data.CodeVerifier))
+	return provider.AuthURL(opts...)
+}
+
+// Callback verifies the state, exchanges the code and returns the user.
+// The id_token is verified with the stored nonce when the provider supports it.
+func (f *WebFlow) Callback(ctx context.Context, state, code string) (OAuthData, *StateData, error) {
+	data, err := f.store.Consume(ctx, state)
+	if err != nil {
+		return OAuthData{}, nil, err
+	}
+	p, err := ParseAuthProvider(data.Provider)
+	if err != nil {
+		return OAuthData{}, nil, err
+	}
+	provider, err := Get(p)
+	if err != nil {
+		return OAuthData{}, nil, err
+	}
+	user, err := f.complete(ctx, provider, state, data, code)
+	return user, data, err
+}
+
+// CallbackWith is Callback with an explicit provider; the state must belong to p
+func (f *WebFlow) CallbackWith(ctx context.Context, p AuthProvider, provider Provider, state, code string) (OAuthData, *StateData, error) {
+	data, err := f.store.Consume(ctx, state)
+	if err != nil {
+		return OAuthData{}, nil, err
+	}
+	if data.Provider != p.String() {
+		return OAuthData{}, nil, fmt.Errorf("%w: provider mismatch", ErrInvalidState)
+	}
+	user, err := f.complete(ctx, provider, state, data, code)
+	return user, data, err
+}
+
+// complete exchanges the code and validates the resulting token.
+// The state is passed on because some providers (ex. Naver) require it for the exchange.
+func (f *WebFlow) complete(ctx context.Context, provider Provider, state string, data *StateData, code string) (OAuthData, error) {
+	opts := []AuthOption{WithState(state), WithPKCE(data.CodeVerifier)}
+	if data.RedirectURL != "" {
+		opts = append(opts, WithRedirectURL(data.RedirectURL))
+	}
+	token, err := provider.ExchangeCode(ctx, code, opts...)
+	if err != nil {
+		return OAuthData{}, err
+	}
+	if validator, ok := provider.(IDTokenValidator); ok && token.IDToken != "" {
+		return validator.ValidateIDToken(ctx, token.IDToken, data.Nonce)
+	}
+	return provider.Validate(ctx, token.AccessToken)
+}
+
+// randomString returns n random bytes encoded with base64url
+func randomString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
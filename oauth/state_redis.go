@@ -0,0 +1,51 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStateStore stores oauth state in Redis
+type RedisStateStore struct {
+	service *_redis.RedisService
+}
+
+// NewRedisStateStore creates a Redis-backed state store
+func NewRedisStateStore(service *_redis.RedisService) *RedisStateStore {
+	return &RedisStateStore{service: service}
+}
+
+func (s *RedisStateStore) Save(ctx context.Context, state string, data StateData, ttl time.Duration) error {
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return s.service.SetKey(ctx, _redis.AuthOAuthStateKey+state, value, ttl)
+}
+
+// Consume uses GETDEL so a state can be used only once
+func (s *RedisStateStore) Consume(ctx context.Context, state string) (*StateData, error) {
+	if state == "" {
+		return nil, ErrInvalidState
+	}
+	client, err := s.service.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := client.GetDel(ctx, _redis.AuthOAuthStateKey+state).Bytes()
+	if err == redis.Nil {
+		return nil, ErrInvalidState
+	}
+	if err != nil {
+		return nil, err
+	}
+	var data StateData
+	if err := json.Unmarshal(value, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,94 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	_redis "github.com/JokerTrickster/common/db/redis"
+	"github.com/alicebob/miniredis/v2"
+)
+
+// newTestStateStore returns a state store backed by an in-process Redis server
+func newTestStateStore(t *testing.T) (*RedisStateStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	service := &_redis.RedisService{}
+	if err := service.Initialize(context.Background(), "redis://"+server.Addr()); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	return NewRedisStateStore(service), server
+}
+
+func TestRedisStateStoreConsume(t *testing.T) {
+	ctx := context.Background()
+	store, server := newTestStateStore(t)
+	saved := StateData{Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", ReturnTo: "/home"}
+	for _, state := range []string{"state-1", "state-expired"} {
+		if err := store.Save(ctx, state, saved, time.Minute); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	server.SetTTL(_redis.AuthOAuthStateKey+"state-expired", time.Second)
+	server.FastForward(2 * time.Second)
+
+	tests := []struct {
+		name    string
+		state   string
+		want    *StateData
+		wantErr error
+	}{
+		{name: "first use", state: "state-1", want: &saved},
+		{name: "second use", state: "state-1", wantErr: ErrInvalidState},
+		{name: "unknown state", state: "state-2", wantErr: ErrInvalidState},
+		{name: "expired state", state: "state-expired", wantErr: ErrInvalidState},
+		{name: "empty state", state: "", wantErr: ErrInvalidState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Consume(ctx, tt.state)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Consume() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.want != nil && (got == nil || *got != *tt.want) {
+				t.Errorf("Consume() = %+v, want %+v", got, tt.want)
+			}
+			if server.Exists(_redis.AuthOAuthStateKey + tt.state) {
+				t.Error("state is still stored after Consume()")
+			}
+		})
+	}
+}
+
+func TestRedisStateStoreConsumeConcurrent(t *testing.T) {
+	ctx := context.Background()
+	store, _ := newTestStateStore(t)
+	if err := store.Save(ctx, "state", StateData{Provider: "google"}, time.Minute); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	consumed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := store.Consume(ctx, "state")
+			if err == nil {
+				mu.Lock()
+				consumed++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrInvalidState) {
+				t.Errorf("Consume() unexpected error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if consumed != 1 {
+		t.Errorf("successful consumes = %d, want 1", consumed)
+	}
+}
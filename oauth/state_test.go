@@ -0,0 +1,98 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStateStore is an in-memory StateStore for tests
+type memStateStore struct {
+	mu     sync.Mutex
+	states map[string]StateData
+}
+
+func (s *memStateStore) Save(ctx context.Context, state string, data StateData, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state] = data
+	return nil
+}
+
+func (s *memStateStore) Consume(ctx context.Context, state string) (*StateData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.states[state]
+	if !ok {
+		return nil, ErrInvalidState
+	}
+	delete(s.states, state)
+	return &data, nil
+}
+
+// stateCheckingProvider requires the login state on the code exchange like Naver
+type stateCheckingProvider struct {
+	*FakeProvider
+	state string
+}
+
+func (p *stateCheckingProvider) AuthURL(opts ...AuthOption) (string, error) {
+	p.state = ApplyAuthOptions(opts...).State
+	return p.FakeProvider.AuthURL(opts...)
+}
+
+func (p *stateCheckingProvider) ExchangeCode(ctx context.Context, code string, opts ...AuthOption) (*Token, error) {
+	if got := ApplyAuthOptions(opts...).State; got == "" || got != p.state {
+		return nil, fmt.Errorf("state = %q, want %q", got, p.state)
+	}
+	return p.FakeProvider.ExchangeCode(ctx, code, opts...)
+}
+
+func TestWebFlowCallback(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		state   func(loginState string) string
+		reuse   bool
+		wantErr error
+	}{
+		{name: "valid state", state: func(s string) string { return s }},
+		{name: "unknown state", state: func(s string) string { return "unknown" }, wantErr: ErrInvalidState},
+		{name: "reused state", state: func(s string) string { return s }, reuse: true, wantErr: ErrInvalidState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &stateCheckingProvider{FakeProvider: NewFakeProvider("naver")}
+			provider.Users["access"] = OAuthData{ID: "1", Email: "user@example.com"}
+			provider.Codes["code"] = &Token{AccessToken: "access"}
+			flow := NewWebFlow(&memStateStore{states: map[string]StateData{}}, time.Minute)
+
+			loginURL, err := flow.BeginWith(ctx, AuthProviderNaver, provider, "/home")
+			if err != nil {
+				t.Fatalf("BeginWith() error = %v", err)
+			}
+			parsed, err := url.Parse(loginURL)
+			if err != nil {
+				t.Fatalf("url.Parse() error = %v", err)
+			}
+			state := tt.state(parsed.Query().Get("state"))
+			if tt.reuse {
+				if _, _, err := flow.CallbackWith(ctx, AuthProviderNaver, provider, state, "code"); err != nil {
+					t.Fatalf("first CallbackWith() error = %v", err)
+				}
+			}
+
+			user, data, err := flow.CallbackWith(ctx, AuthProviderNaver, provider, state, "code")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CallbackWith() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && (user.ID != "1" || data.ReturnTo != "/home") {
+				t.Errorf("CallbackWith() = %+v, %+v, want user 1 returning to /home", user, data)
+			}
+		})
+	}
+}
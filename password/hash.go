@@ -0,0 +1,197 @@
+package password
+
+/*
+	비밀번호 해싱 (Argon2id 기본, 레거시 bcrypt 지원)
+	- 해시 문자열에 파라미터를 포함하므로 파라미터를 올려도 기존 해시를 검증할 수 있습니다.
+	- 로그인 시 NeedsRehash 로 확인 후 더 강한 파라미터로 재해싱합니다.
+*/
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	// ErrInvalidHash is returned when the encoded hash cannot be parsed
+	ErrInvalidHash = errors.New("invalid password hash")
+	// ErrIncompatibleVersion is returned for hashes made with another argon2 version
+	ErrIncompatibleVersion = errors.New("incompatible argon2 version")
+)
+
+// Params are the Argon2id parameters
+type Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follow the OWASP recommendation for Argon2id
+var DefaultParams = Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// DefaultBcryptCost is used by HashBcrypt when cost is 0
+const DefaultBcryptCost = 12
+
+var (
+	mu     sync.RWMutex
+	params = DefaultParams
+)
+
+// SetParams sets the parameters used by Hash and compared by NeedsRehash
+func SetParams(p Params) error {
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength < 8 || p.KeyLength < 16 {
+		return fmt.Errorf("invalid argon2 params: %+v", p)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	params = p
+	return nil
+}
+
+// currentParams returns the configured parameters
+func currentParams() Params {
+	mu.RLock()
+	defer mu.RUnlock()
+	return params
+}
+
+// Hash hashes a password with Argon2id and the configured parameters
+func Hash(plain string) (string, error) {
+	return HashWithParams(plain, currentParams())
+}
+
+// HashWithParams hashes a password with Argon2id.
+// Format: $argon2id$v=19$m=65536,t=3,p=2$<base64 salt>$<base64 key>
+func HashWithParams(plain string, p Params) (string, error) {
+	salt := make([]byte, p.SaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// HashBcrypt hashes a password with bcrypt (for services that still need it)
+func HashBcrypt(plain string, cost int) (string, error) {
+	if cost == 0 {
+		cost = DefaultBcryptCost
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), cost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify reports whether the password matches an Argon2id or bcrypt hash.
+// The comparison is constant time; a mismatch returns false with a nil error.
+func Verify(plain, encoded string) (bool, error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidHash, err)
+		}
+		return true, nil
+	}
+
+	p, salt, key, err := decodeArgon2(encoded)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// NeedsRehash reports whether the hash is bcrypt or uses weaker parameters than configured
+func NeedsRehash(encoded string) bool {
+	if isBcrypt(encoded) {
+		return true
+	}
+	p, _, _, err := decodeArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	want := currentParams()
+	return p.Memory < want.Memory || p.Iterations < want.Iterations ||
+		p.Parallelism < want.Parallelism || p.SaltLength < want.SaltLength || p.KeyLength < want.KeyLength
+}
+
+// VerifyAndUpgrade verifies the password and returns a new hash when the stored one needs rehashing.
+// newHash is empty when the password does not match or the hash is up to date.
+//
+//	ok, newHash, err := password.VerifyAndUpgrade(req.Password, user.Password)
+//	if ok && newHash != "" { // save newHash }
+func VerifyAndUpgrade(plain, encoded string) (ok bool, newHash string, err error) {
+	ok, err = Verify(plain, encoded)
+	if err != nil || !ok {
+		return false, "", err
+	}
+	if !NeedsRehash(encoded) {
+		return true, "", nil
+	}
+	newHash, err = Hash(plain)
+	if err != nil {
+		return true, "", err
+	}
+	return true, newHash, nil
+}
+
+// isBcrypt reports whether the hash has a bcrypt prefix
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// decodeArgon2 parses an encoded Argon2id hash
+func decodeArgon2(encoded string) (Params, []byte, []byte, error) {
+	var p Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	if version != argon2.Version {
+		return p, nil, nil, ErrIncompatibleVersion
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, ErrInvalidHash
+	}
+	p.SaltLength = uint32(len(salt))
+	p.KeyLength = uint32(len(key))
+	return p, salt, key, nil
+}
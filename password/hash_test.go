@@ -0,0 +1,156 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testParams keep Argon2id fast in tests
+var testParams = Params{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// useParams sets the Argon2id parameters for the duration of the test
+func useParams(t *testing.T, p Params) {
+	t.Helper()
+	if err := SetParams(p); err != nil {
+		t.Fatalf("SetParams() error = %v", err)
+	}
+	t.Cleanup(func() { _ = SetParams(DefaultParams) })
+}
+
+func TestHashVerify(t *testing.T) {
+	useParams(t, testParams)
+	tests := []struct {
+		name       string
+		hash       func(plain string) (string, error)
+		wantPrefix string
+		wantRehash bool
+	}{
+		{name: "argon2id", hash: Hash, wantPrefix: "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{name: "bcrypt", hash: func(plain string) (string, error) { return HashBcrypt(plain, bcrypt.MinCost) }, wantPrefix: "$2a$", wantRehash: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.hash("correct horse")
+			if err != nil {
+				t.Fatalf("hash error = %v", err)
+			}
+			if !strings.HasPrefix(encoded, tt.wantPrefix) {
+				t.Errorf("hash = %q, want prefix %q", encoded, tt.wantPrefix)
+			}
+			if ok, err := Verify("correct horse", encoded); !ok || err != nil {
+				t.Errorf("Verify(correct) = %v, %v, want true, nil", ok, err)
+			}
+			if ok, err := Verify("wrong horse", encoded); ok || err != nil {
+				t.Errorf("Verify(wrong) = %v, %v, want false, nil", ok, err)
+			}
+			if got := NeedsRehash(encoded); got != tt.wantRehash {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.wantRehash)
+			}
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	encoded, err := HashWithParams("correct horse", testParams)
+	if err != nil {
+		t.Fatalf("HashWithParams() error = %v", err)
+	}
+	with := func(change func(p *Params)) Params {
+		p := testParams
+		change(&p)
+		return p
+	}
+	tests := []struct {
+		name   string
+		params Params
+		want   bool
+	}{
+		{name: "same params", params: testParams, want: false},
+		{name: "weaker params", params: with(func(p *Params) { p.Memory = 512 }), want: false},
+		{name: "more memory", params: with(func(p *Params) { p.Memory = 2048 }), want: true},
+		{name: "more iterations", params: with(func(p *Params) { p.Iterations = 2 }), want: true},
+		{name: "more parallelism", params: with(func(p *Params) { p.Parallelism = 2 }), want: true},
+		{name: "longer salt", params: with(func(p *Params) { p.SaltLength = 32 }), want: true},
+		{name: "longer key", params: with(func(p *Params) { p.KeyLength = 64 }), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useParams(t, tt.params)
+			if got := NeedsRehash(encoded); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAndUpgrade(t *testing.T) {
+	useParams(t, testParams)
+	legacy, err := HashBcrypt("correct horse", bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("HashBcrypt() error = %v", err)
+	}
+	current, err := Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	tests := []struct {
+		name        string
+		plain       string
+		encoded     string
+		wantOK      bool
+		wantUpgrade bool
+	}{
+		{name: "bcrypt hash is upgraded", plain: "correct horse", encoded: legacy, wantOK: true, wantUpgrade: true},
+		{name: "current hash is kept", plain: "correct horse", encoded: current, wantOK: true},
+		{name: "wrong password", plain: "wrong horse", encoded: legacy},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, newHash, err := VerifyAndUpgrade(tt.plain, tt.encoded)
+			if err != nil {
+				t.Fatalf("VerifyAndUpgrade() error = %v", err)
+			}
+			if ok != tt.wantOK || (newHash != "") != tt.wantUpgrade {
+				t.Fatalf("VerifyAndUpgrade() = %v, %q, want %v, upgrade %v", ok, newHash, tt.wantOK, tt.wantUpgrade)
+			}
+			if tt.wantUpgrade {
+				if ok, err := Verify(tt.plain, newHash); !ok || err != nil || NeedsRehash(newHash) {
+					t.Errorf("upgraded hash %q does not verify or needs rehash", newHash)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyMalformedHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded string
+		wantErr error
+	}{
+		{name: "empty", encoded: "", wantErr: ErrInvalidHash},
+		{name: "plain text", encoded: "correct horse", wantErr: ErrInvalidHash},
+		{name: "other algorithm", encoded: "$argon2i$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ$a2V5", wantErr: ErrInvalidHash},
+		{name: "missing key", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ", wantErr: ErrInvalidHash},
+		{name: "bad version", encoded: "$argon2id$v=x$m=1024,t=1,p=1$c2FsdHNhbHQ$a2V5", wantErr: ErrInvalidHash},
+		{name: "other version", encoded: "$argon2id$v=16$m=1024,t=1,p=1$c2FsdHNhbHQ$a2V5", wantErr: ErrIncompatibleVersion},
+		{name: "bad params", encoded: "$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5", wantErr: ErrInvalidHash},
+		{name: "bad salt", encoded: "$argon2id$v=19$m=1024,t=1,p=1$!!!$a2V5", wantErr: ErrInvalidHash},
+		{name: "empty key", encoded: "$argon2id$v=19$m=1024,t=1,p=1$c2FsdHNhbHQ$", wantErr: ErrInvalidHash},
+		{name: "truncated bcrypt", encoded: "$2a$04$short", wantErr: ErrInvalidHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := Verify("correct horse", tt.encoded)
+			if ok || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() = %v, %v, want false, %v", ok, err, tt.wantErr)
+			}
+			if !NeedsRehash(tt.encoded) {
+				t.Error("NeedsRehash() = false, want true")
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package password
+
+/*
+	비밀번호 정책 검증
+	- validator 패키지의 `validate:"password"` 규칙이 CurrentPolicy 를 사용합니다.
+*/
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+// Policy defines the password rules
+type Policy struct {
+	MinLength      int
+	MaxLength      int // 0 means no limit
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	ForbiddenWords []string // case-insensitive substrings (ex. service name)
+}
+
+// DefaultPolicy requires 8-64 characters with letters and digits
+var DefaultPolicy = Policy{
+	MinLength:    8,
+	MaxLength:    64,
+	RequireLower: true,
+	RequireDigit: true,
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = DefaultPolicy
+)
+
+// SetPolicy sets the policy used by CurrentPolicy and the validator rule
+func SetPolicy(p Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy = p
+}
+
+// CurrentPolicy returns the configured policy
+func CurrentPolicy() Policy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return policy
+}
+
+// Violations returns the rules the password breaks (empty when valid)
+func (p Policy) Violations(plain string) []string {
+	var violations []string
+	length := utf8.RuneCountInString(plain)
+	if length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d characters", p.MaxLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+	if p.RequireUpper && !upper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(plain)
+	for _, word := range p.ForbiddenWords {
+		if word != "" && strings.Contains(lowered, strings.ToLower(word)) {
+			violations = append(violations, "must not contain a forbidden word")
+			break
+		}
+	}
+	return violations
+}
+
+// Validate returns a WEAK_PASSWORD error listing the violations
+func (p Policy) Validate(ctx context.Context, plain string) error {
+	violations := p.Violations(plain)
+	if len(violations) == 0 {
+		return nil
+	}
+	return _error.NewAppError(ctx, string(_error.ErrWeakPassword), _error.Trace(), "password "+strings.Join(violations, ", "), string(_error.ErrFromClient)).
+		WithDetail("violations", violations)
+}
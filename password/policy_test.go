@@ -0,0 +1,70 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	_error "github.com/JokerTrickster/common/error"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	strict := Policy{
+		MinLength:      8,
+		MaxLength:      16,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		ForbiddenWords: []string{"food"},
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		plain  string
+		want   []string
+	}{
+		{name: "valid", policy: strict, plain: "Secur3!pass"},
+		{name: "too short", policy: strict, plain: "Ab3!", want: []string{"must be at least 8 characters"}},
+		{name: "too long", policy: strict, plain: "Abcdefgh3!abcdefg", want: []string{"must be at most 16 characters"}},
+		{name: "length counts runes", policy: Policy{MinLength: 4}, plain: "비밀번호"},
+		{name: "no max length", policy: Policy{MinLength: 1}, plain: strings.Repeat("a", 100)},
+		{name: "no uppercase", policy: strict, plain: "secur3!pass", want: []string{"must contain an uppercase letter"}},
+		{name: "no lowercase", policy: strict, plain: "SECUR3!PASS", want: []string{"must contain a lowercase letter"}},
+		{name: "no digit", policy: strict, plain: "Secure!pass", want: []string{"must contain a digit"}},
+		{name: "no symbol", policy: strict, plain: "Secur3xpass", want: []string{"must contain a symbol"}},
+		{name: "forbidden word in any case", policy: strict, plain: "My!F00DFood1", want: []string{"must not contain a forbidden word"}},
+		{
+			name:   "several rules",
+			policy: DefaultPolicy,
+			plain:  "ABC",
+			want:   []string{"must be at least 8 characters", "must contain a lowercase letter", "must contain a digit"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Violations(tt.plain); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Violations() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	ctx := context.Background()
+	if err := DefaultPolicy.Validate(ctx, "secure123"); err != nil {
+		t.Fatalf("Validate(valid) error = %v", err)
+	}
+
+	err := DefaultPolicy.Validate(ctx, "short")
+	var appErr *_error.AppError
+	if !errors.As(err, &appErr) || !errors.Is(err, _error.ErrWeakPassword) {
+		t.Fatalf("Validate() error = %v, want %s", err, _error.ErrWeakPassword)
+	}
+	want := []string{"must be at least 8 characters", "must contain a digit"}
+	if got := appErr.Details["violations"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("violations detail = %v, want %v", got, want)
+	}
+}
@@ -5,6 +5,7 @@ package validator
 */
 
 import (
+	"github.com/JokerTrickster/common/password"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -15,6 +16,11 @@ func RegisterCustomRules(v *validator.Validate) {
 		// Add your custom rule logic here
 		return len(fl.Field().String()) > 5 // Example: Field must be longer than 5 characters
 	})
+
+	// password: checks the field against password.CurrentPolicy()
+	_ = v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return len(password.CurrentPolicy().Violations(fl.Field().String())) == 0
+	})
 }
 
 // InitValidator initializes the validator instance with custom rules
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/JokerTrickster/common/password"
+)
+
+func TestPasswordRule(t *testing.T) {
+	type signupReq struct {
+		Password string `validate:"password"`
+	}
+	tests := []struct {
+		name    string
+		policy  password.Policy
+		plain   string
+		wantErr bool
+	}{
+		{name: "default policy", policy: password.DefaultPolicy, plain: "secure123"},
+		{name: "default policy without digit", policy: password.DefaultPolicy, plain: "securepass", wantErr: true},
+		{name: "configured policy", policy: password.Policy{MinLength: 4, RequireSymbol: true}, plain: "ab!d"},
+		{name: "configured policy without symbol", policy: password.Policy{MinLength: 4, RequireSymbol: true}, plain: "secure123", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password.SetPolicy(tt.policy)
+			t.Cleanup(func() { password.SetPolicy(password.DefaultPolicy) })
+
+			err := ValidateStruct(signupReq{Password: tt.plain})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStruct() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
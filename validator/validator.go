@@ -13,7 +13,7 @@ import (
 )
 
 // Validator instance
-var val = validator.New()
+var val *validator.Validate = InitValidator()
 
 // ValidateStruct validates a given struct
 func ValidateStruct(class interface{}) error {